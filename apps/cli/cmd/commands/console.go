@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/console"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deployconfig"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+func setupConsoleCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	consoleCmd := &cobra.Command{
+		Use:   "console",
+		Short: "Interactive console for evaluating blueprint expressions",
+		Long: `Starts an interactive console that loads a blueprint and lets you evaluate
+	substitution expressions (e.g. variables.region or to_upper(values.name))
+	against it.
+
+	Blueprint variables are sourced from the deploy config file with blueprint defaults
+	used as a fallback. The state of a deployed blueprint instance can optionally be loaded
+	from the deploy engine to inspect resources and exports.
+
+	Provider plugins are not loaded into the console, references to resources
+	(e.g. resources.ordersTable.spec.tableName) resolve to the deployed values in the
+	loaded instance and references to data sources resolve to the data source fields
+	that are recorded in the instance through blueprint exports.
+	Values derived from secret variables are masked.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			blueprintFile, _ := confProvider.GetString("consoleBlueprintFile")
+			bp, _, err := blueprint.LoadForDev(blueprintFile)
+			if err != nil {
+				return err
+			}
+
+			deployConfigFile, isDefault := confProvider.GetString("deployConfigFile")
+			deployConfig, err := deployconfig.Load(deployConfigFile, isDefault)
+			if err != nil {
+				return err
+			}
+
+			instance, err := loadConsoleInstance(cmd, confProvider)
+			if err != nil {
+				return err
+			}
+
+			session := console.NewSession(bp, deployConfig, instance)
+			return console.NewREPL(session, os.Stdin, os.Stdout).Run(cmd.Context())
+		},
+	}
+
+	consoleCmd.PersistentFlags().StringP(
+		"blueprint-file",
+		"b",
+		"app.blueprint.yaml",
+		"The blueprint file to load into the console.",
+	)
+	confProvider.BindPFlag("consoleBlueprintFile", consoleCmd.PersistentFlags().Lookup("blueprint-file"))
+	confProvider.BindEnvVar("consoleBlueprintFile", "CELERITY_CLI_CONSOLE_BLUEPRINT_FILE")
+
+	consoleCmd.PersistentFlags().StringP(
+		"instance",
+		"i",
+		"",
+		"The ID of a deployed blueprint instance to load state for from the deploy engine.",
+	)
+	confProvider.BindPFlag("consoleInstance", consoleCmd.PersistentFlags().Lookup("instance"))
+	confProvider.BindEnvVar("consoleInstance", "CELERITY_CLI_CONSOLE_INSTANCE")
+
+	rootCmd.AddCommand(consoleCmd)
+}
+
+func loadConsoleInstance(cmd *cobra.Command, confProvider *config.Provider) (*state.InstanceState, error) {
+	instanceID, _ := confProvider.GetString("consoleInstance")
+	if instanceID == "" {
+		return nil, nil
+	}
+
+	logger, handle, err := utils.SetupLogger()
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Close()
+
+	deployEngine, err := engine.Create(confProvider, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := deployEngine.GetBlueprintInstance(cmd.Context(), instanceID)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+
+	return instance, nil
+}
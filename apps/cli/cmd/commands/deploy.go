@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deployconfig"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/deployui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func setupDeployCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploys a Celerity blueprint",
+		Long: `Stages changes for a Celerity blueprint, shows a summary of the changes
+	and deploys them once confirmed.
+
+	The progress of each resource, link and child blueprint is shown as the deployment
+	happens, including the progress of rolling back changes when the deployment fails.
+
+	When an instance ID is provided, the changes are staged against the existing
+	blueprint instance and the instance is updated, otherwise a new instance is deployed.
+	In a non-interactive environment, changes are only deployed when the --auto-approve
+	flag is set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := utils.SetupLogger()
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			deployConfigFile, isDefault := confProvider.GetString("deployConfigFile")
+			deployConfig, err := deployconfig.Load(deployConfigFile, isDefault)
+			if err != nil {
+				return err
+			}
+
+			blueprintFile, _ := confProvider.GetString("deployBlueprintFile")
+			instanceID, _ := confProvider.GetString("deployInstanceID")
+			opts := &deploy.Options{
+				BlueprintFile: blueprintFile,
+				InstanceID:    instanceID,
+				Config:        deployConfig,
+			}
+			autoApprove, _ := confProvider.GetBool("deployAutoApprove")
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				handler := handlers.NewDeployHandler(
+					deployEngine,
+					opts,
+					autoApprove,
+					os.Stdout,
+					logger,
+				)
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+
+				return handler.Handle(ctx)
+			}
+
+			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
+				log.Fatal(err)
+			}
+
+			styles := styles.NewDefaultCelerityStyles()
+			app := deployui.NewDeployApp(deployEngine, logger, opts, styles)
+			finalModel, err := tea.NewProgram(app).Run()
+			if err != nil {
+				return err
+			}
+			finalApp := finalModel.(deployui.DeployModel)
+
+			if finalApp.Error != nil {
+				// The error is the outcome of the deployment,
+				// not a problem with how the command was used.
+				cmd.SilenceUsage = true
+				return finalApp.Error
+			}
+
+			return nil
+		},
+	}
+
+	deployCmd.PersistentFlags().StringP(
+		"blueprint-file",
+		"b",
+		"app.blueprint.yaml",
+		"The blueprint file to deploy.",
+	)
+	confProvider.BindPFlag("deployBlueprintFile", deployCmd.PersistentFlags().Lookup("blueprint-file"))
+	confProvider.BindEnvVar("deployBlueprintFile", "CELERITY_CLI_DEPLOY_BLUEPRINT_FILE")
+
+	deployCmd.PersistentFlags().StringP(
+		"instance-id",
+		"i",
+		"",
+		"The ID of an existing blueprint instance to update, "+
+			"a new blueprint instance is deployed when this is not set.",
+	)
+	confProvider.BindPFlag("deployInstanceID", deployCmd.PersistentFlags().Lookup("instance-id"))
+	confProvider.BindEnvVar("deployInstanceID", "CELERITY_CLI_DEPLOY_INSTANCE_ID")
+
+	deployCmd.PersistentFlags().Bool(
+		"auto-approve",
+		false,
+		"Deploy staged changes without asking for confirmation, "+
+			"this is required to deploy in a non-interactive environment.",
+	)
+	confProvider.BindPFlag("deployAutoApprove", deployCmd.PersistentFlags().Lookup("auto-approve"))
+	confProvider.BindEnvVar("deployAutoApprove", "CELERITY_CLI_DEPLOY_AUTO_APPROVE")
+
+	rootCmd.AddCommand(deployCmd)
+}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/docs"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/docsui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func setupDocsCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	docsCmd := &cobra.Command{
+		Use:   "docs [resource-type]",
+		Short: "Browse the documentation for resource types provided by installed plugins",
+		Long: `Browse the documentation for the resource types provided by the plugins installed
+for the deploy engine, including the fields of the resource spec, examples
+and the resource types it can link to. No internet access is needed.
+
+Documentation is loaded from the docs.json file included with each installed
+plugin and from the docs cache directory, installed plugins take precedence over the cache.
+Run "celerity docs sync" to download the documentation published with the releases of
+installed plugins that do not include a docs.json file to the cache directory.
+The JSON files produced by the plugin docs generator can also be copied to the cache
+directory by hand to browse the docs for plugins that are not installed.
+
+When the resource type does not match exactly, it is used as a search query
+across resource types, labels and summaries.
+In an interactive terminal, a searchable browser is opened.`,
+		Annotations: map[string]string{skipConfigFileAnnotation: "true"},
+		Example: `  celerity docs aws/lambda/function
+  celerity docs dynamodb`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := loadDocsCatalog(confProvider)
+			if err != nil {
+				return err
+			}
+
+			for _, skipped := range catalog.Skipped {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", skipped)
+			}
+
+			if len(catalog.Entries) == 0 {
+				return errors.New(
+					"no plugin documentation found, install provider plugins with " +
+						"\"celerity plugins install\" and download their docs with \"celerity docs sync\"",
+				)
+			}
+
+			query := ""
+			if len(args) > 0 {
+				query = args[0]
+			}
+			selected, _ := catalog.Lookup(query)
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				return printDocs(cmd.OutOrStdout(), catalog, query, selected)
+			}
+
+			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
+				log.Fatal(err)
+			}
+
+			app := docsui.NewDocsApp(catalog, query, selected)
+			_, err = tea.NewProgram(app, tea.WithAltScreen()).Run()
+			return err
+		},
+	}
+
+	docsCmd.PersistentFlags().String(
+		"plugin-dir",
+		"",
+		"The directory plugins are installed to, this defaults to the first directory in "+
+			plugins.PluginPathEnvVar+" or ~/.bluelink/engine/plugins/bin when it is not set.",
+	)
+	confProvider.BindPFlag("docsPluginDir", docsCmd.PersistentFlags().Lookup("plugin-dir"))
+	confProvider.BindEnvVar("docsPluginDir", "CELERITY_CLI_PLUGINS_DIR")
+
+	docsCmd.PersistentFlags().String(
+		"cache-dir",
+		"",
+		"The directory plugin docs are downloaded to and loaded from for plugins that do not include docs, "+
+			"this defaults to the celerity/plugin-docs directory in the user cache directory.",
+	)
+	confProvider.BindPFlag("docsCacheDir", docsCmd.PersistentFlags().Lookup("cache-dir"))
+	confProvider.BindEnvVar("docsCacheDir", "CELERITY_CLI_DOCS_CACHE_DIR")
+
+	setupDocsSyncCommand(docsCmd, confProvider)
+	rootCmd.AddCommand(docsCmd)
+}
+
+func setupDocsSyncCommand(docsCmd *cobra.Command, confProvider *config.Provider) {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Download the documentation for installed plugins",
+		Long: `Download the documentation published with the release of the latest installed
+version of each plugin that does not include a docs.json file to the docs cache directory,
+so it can be browsed with "celerity docs" without internet access.
+
+Plugin releases publish documentation with a "docsUrl" in the registry download
+response or a docs.json release asset for plugins installed from GitHub.
+Run this command again after upgrading plugins to keep the cached docs in sync.`,
+		Example: `  celerity docs sync`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			layout, cacheDir, err := docsLocations(confProvider)
+			if err != nil {
+				return err
+			}
+
+			publicKey, _ := confProvider.GetString("docsPublicKey")
+			installer, err := pluginsInstallerForLayout(layout, publicKey)
+			if err != nil {
+				return err
+			}
+
+			results, err := docs.Sync(cmd.Context(), layout, cacheDir, installer)
+			if err != nil {
+				return err
+			}
+
+			if len(results) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No plugins are installed.")
+				return nil
+			}
+
+			cmd.SilenceUsage = true
+			return printDocsSyncResults(cmd.OutOrStdout(), results)
+		},
+	}
+
+	syncCmd.Flags().String(
+		"public-key",
+		"",
+		"A base64 encoded ed25519 public key used to verify the signature of the checksums "+
+			"that downloaded docs are checked against.",
+	)
+	confProvider.BindPFlag("docsPublicKey", syncCmd.Flags().Lookup("public-key"))
+	confProvider.BindEnvVar("docsPublicKey", "CELERITY_CLI_PLUGINS_PUBLIC_KEY")
+
+	docsCmd.AddCommand(syncCmd)
+}
+
+func printDocsSyncResults(out io.Writer, results []*docs.SyncResult) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PLUGIN\tVERSION\tDOCS")
+	failed := 0
+	for _, result := range results {
+		status := string(result.Status)
+		if result.Err != nil {
+			failed += 1
+			status = fmt.Sprintf("%s: %s", status, result.Err)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Plugin.ID, result.Plugin.Version, status)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to download the docs for %d plugin(s)", failed)
+	}
+	return nil
+}
+
+func loadDocsCatalog(confProvider *config.Provider) (*docs.Catalog, error) {
+	layout, cacheDir, err := docsLocations(confProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return docs.LoadCatalog(layout, cacheDir)
+}
+
+func docsLocations(confProvider *config.Provider) (plugins.Layout, string, error) {
+	pluginDir, _ := confProvider.GetString("docsPluginDir")
+	layout, err := pluginsLayoutForDir(pluginDir)
+	if err != nil {
+		return plugins.Layout{}, "", err
+	}
+
+	cacheDir, _ := confProvider.GetString("docsCacheDir")
+	if cacheDir == "" {
+		cacheDir, err = docs.DefaultCacheDir()
+		if err != nil {
+			return plugins.Layout{}, "", err
+		}
+	}
+
+	return layout, cacheDir, nil
+}
+
+func printDocs(out io.Writer, catalog *docs.Catalog, query string, selected *docs.Entry) error {
+	if selected != nil {
+		_, err := fmt.Fprint(out, docs.Render(selected, docs.RenderOptions{}))
+		return err
+	}
+
+	matches := catalog.Search(query)
+	if len(matches) == 0 {
+		return fmt.Errorf("no resource types match %q", query)
+	}
+
+	if len(matches) == 1 {
+		_, err := fmt.Fprint(out, docs.Render(matches[0], docs.RenderOptions{}))
+		return err
+	}
+
+	if query != "" {
+		fmt.Fprintf(out, "No resource type named %q, the following resource types match:\n\n", query)
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "RESOURCE TYPE\tLABEL\tPLUGIN")
+	for _, entry := range matches {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", entry.Resource.Type, entry.Resource.Label, entry.Plugin.ID)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func setupDriftCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Commands for detecting drift in deployed blueprint instances",
+		Long: `Detect drift between the resources deployed for blueprint instances
+and the state recorded in the deploy engine.
+
+  celerity drift watch   Periodically check instances for drift`,
+	}
+
+	setupDriftWatchCommand(driftCmd, confProvider)
+
+	rootCmd.AddCommand(driftCmd)
+}
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deployconfig"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/drift"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/driftui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const minDriftWatchInterval = 10 * time.Second
+
+func setupDriftWatchCommand(driftCmd *cobra.Command, confProvider *config.Provider) {
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Periodically checks blueprint instances for drift",
+		Long: `Periodically checks the selected blueprint instances for drift and shows
+	a live-updating table of the drifted resources.
+
+	Drift checks are triggered by staging changes for each instance against the blueprint
+	file it was deployed from, the deploy engine checks the resources in the instance for drift
+	as a part of change staging. No changes are deployed.
+
+	Each check creates a change set in the deploy engine that is never deployed.
+	After each round of checks, the deploy engine's change set clean up is triggered
+	to remove change sets that are older than the retention period configured
+	for the deploy engine, so change sets from recent checks are kept until then.
+
+	When notifications are enabled, the terminal bell is rung and/or a desktop notification
+	is shown when new drift is detected. Drift that exists when watching starts is shown
+	in the table but does not trigger a notification, drift that persists between checks
+	is only notified once, unless it goes away and is detected again in a later check.
+	In a non-interactive environment, the results of each check are written to stdout.`,
+		Example: `  celerity drift watch --instance-ids orders-api-prod
+  celerity drift watch --instance-ids orders-api-prod,payments-prod=payments/app.blueprint.yaml \
+    --interval 2m --notify bell,desktop`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			blueprintFile, _ := confProvider.GetString("driftBlueprintFile")
+			instanceIDs, _ := confProvider.GetString("driftInstanceIDs")
+			targets, err := drift.ParseTargets(splitList(instanceIDs), blueprintFile)
+			if err != nil {
+				return err
+			}
+
+			intervalValue, _ := confProvider.GetString("driftInterval")
+			interval, err := parseDriftWatchInterval(intervalValue)
+			if err != nil {
+				return err
+			}
+
+			notify, _ := confProvider.GetString("driftNotify")
+			notifiers, err := drift.ParseNotifiers(splitList(notify), os.Stdout)
+			if err != nil {
+				return err
+			}
+
+			logger, handle, err := utils.SetupLogger()
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			deployConfigFile, isDefault := confProvider.GetString("deployConfigFile")
+			deployConfig, err := deployconfig.Load(deployConfigFile, isDefault)
+			if err != nil {
+				return err
+			}
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+
+				handler := handlers.NewDriftWatchHandler(
+					deployEngine,
+					targets,
+					deployConfig,
+					interval,
+					notifiers,
+					os.Stdout,
+					logger,
+				)
+				return handler.Handle(ctx)
+			}
+
+			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
+				log.Fatal(err)
+			}
+
+			app := driftui.NewDriftWatchApp(
+				deployEngine,
+				logger,
+				targets,
+				deployConfig,
+				interval,
+				notifiers,
+			)
+			_, err = tea.NewProgram(app).Run()
+			return err
+		},
+	}
+
+	watchCmd.PersistentFlags().StringP(
+		"instance-ids",
+		"i",
+		"",
+		"A comma-separated list of the IDs of the blueprint instances to watch. "+
+			"An instance can be deployed from a different blueprint file to the one set "+
+			"with --blueprint-file by using the form \"{instanceID}={blueprintFile}\".",
+	)
+	confProvider.BindPFlag("driftInstanceIDs", watchCmd.PersistentFlags().Lookup("instance-ids"))
+	confProvider.BindEnvVar("driftInstanceIDs", "CELERITY_CLI_DRIFT_INSTANCE_IDS")
+
+	watchCmd.PersistentFlags().StringP(
+		"blueprint-file",
+		"b",
+		"app.blueprint.yaml",
+		"The blueprint file the watched instances were deployed from.",
+	)
+	confProvider.BindPFlag("driftBlueprintFile", watchCmd.PersistentFlags().Lookup("blueprint-file"))
+	confProvider.BindEnvVar("driftBlueprintFile", "CELERITY_CLI_DRIFT_BLUEPRINT_FILE")
+
+	watchCmd.PersistentFlags().String(
+		"interval",
+		"5m",
+		fmt.Sprintf(
+			"How often to check the instances for drift, e.g. \"30s\" or \"5m\". "+
+				"This must be at least %s.",
+			minDriftWatchInterval,
+		),
+	)
+	confProvider.BindPFlag("driftInterval", watchCmd.PersistentFlags().Lookup("interval"))
+	confProvider.BindEnvVar("driftInterval", "CELERITY_CLI_DRIFT_INTERVAL")
+
+	watchCmd.PersistentFlags().String(
+		"notify",
+		"",
+		fmt.Sprintf(
+			"A comma-separated list of the ways to notify you when new drift is detected, "+
+				"this can include %q (terminal bell) and %q (desktop notification).",
+			drift.NotifyBell,
+			drift.NotifyDesktop,
+		),
+	)
+	confProvider.BindPFlag("driftNotify", watchCmd.PersistentFlags().Lookup("notify"))
+	confProvider.BindEnvVar("driftNotify", "CELERITY_CLI_DRIFT_NOTIFY")
+
+	driftCmd.AddCommand(watchCmd)
+}
+
+func parseDriftWatchInterval(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", value, err)
+	}
+
+	if interval < minDriftWatchInterval {
+		return 0, fmt.Errorf("the interval must be at least %s", minDriftWatchInterval)
+	}
+
+	return interval, nil
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+func setupGraphCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	graphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Renders the dependency graph of a blueprint",
+		Long: `Renders the graph of dependencies between the elements of a blueprint
+	to help reason about the order in which elements are deployed.
+
+	Dependencies are derived from references in substitutions, explicit dependsOn
+	declarations and link selectors. The graph can be rendered in the Graphviz DOT language,
+	as a Mermaid flowchart or as a self-contained HTML page with an interactive viewer
+	that draws the graph without loading anything from the network.
+
+	The graph can be restricted to specific element types (variable, value, datasource,
+	resource, child, export) and to the subgraph of a single element, which includes
+	the elements it depends on and the elements that depend on it.`,
+		Example: `  celerity graph --format dot | dot -Tsvg > graph.svg
+  celerity graph --format mermaid --element-types resource,datasource
+  celerity graph --format html --focus resources.ordersTable -o graph.html`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			blueprintFile, _ := confProvider.GetString("graphBlueprintFile")
+			bp, _, err := blueprint.LoadForDev(blueprintFile)
+			if err != nil {
+				return err
+			}
+
+			formatValue, _ := confProvider.GetString("graphFormat")
+			format, err := graph.ParseFormat(formatValue)
+			if err != nil {
+				return err
+			}
+
+			directionValue, _ := confProvider.GetString("graphDirection")
+			direction, err := graph.ParseDirection(directionValue)
+			if err != nil {
+				return err
+			}
+
+			elementTypesValue, _ := confProvider.GetString("graphElementTypes")
+			elementTypes, err := graph.ParseElementTypes(splitList(elementTypesValue))
+			if err != nil {
+				return err
+			}
+
+			focus, _ := confProvider.GetString("graphFocus")
+			filtered, err := graph.Filter(graph.Build(bp), graph.FilterOptions{
+				ElementTypes: elementTypes,
+				Focus:        focus,
+			})
+			if err != nil {
+				return err
+			}
+
+			outputFile, _ := confProvider.GetString("graphOutputFile")
+			return writeGraph(outputFile, func(w io.Writer) error {
+				return graph.Render(w, filtered, format, graph.RenderOptions{
+					Direction: direction,
+					Title:     fmt.Sprintf("%s graph", filepath.Base(blueprintFile)),
+				})
+			})
+		},
+	}
+
+	graphCmd.PersistentFlags().StringP(
+		"blueprint-file",
+		"b",
+		"app.blueprint.yaml",
+		"The blueprint file to render the graph for.",
+	)
+	confProvider.BindPFlag("graphBlueprintFile", graphCmd.PersistentFlags().Lookup("blueprint-file"))
+	confProvider.BindEnvVar("graphBlueprintFile", "CELERITY_CLI_GRAPH_BLUEPRINT_FILE")
+
+	graphCmd.PersistentFlags().StringP(
+		"format",
+		"f",
+		"dot",
+		"The format to render the graph in, one of: dot, mermaid, html.",
+	)
+	confProvider.BindPFlag("graphFormat", graphCmd.PersistentFlags().Lookup("format"))
+	confProvider.BindEnvVar("graphFormat", "CELERITY_CLI_GRAPH_FORMAT")
+
+	graphCmd.PersistentFlags().String(
+		"direction",
+		"LR",
+		"The direction to lay out the graph in, one of: LR, RL, TB, BT.",
+	)
+	confProvider.BindPFlag("graphDirection", graphCmd.PersistentFlags().Lookup("direction"))
+	confProvider.BindEnvVar("graphDirection", "CELERITY_CLI_GRAPH_DIRECTION")
+
+	graphCmd.PersistentFlags().String(
+		"element-types",
+		"",
+		"A comma-separated list of element types to include in the graph "+
+			"(variable, value, datasource, resource, child, export), all element types are included by default.",
+	)
+	confProvider.BindPFlag("graphElementTypes", graphCmd.PersistentFlags().Lookup("element-types"))
+	confProvider.BindEnvVar("graphElementTypes", "CELERITY_CLI_GRAPH_ELEMENT_TYPES")
+
+	graphCmd.PersistentFlags().String(
+		"focus",
+		"",
+		"An element to restrict the graph to along with its dependencies and dependents, "+
+			"in the form <type>.<name>, e.g. resources.ordersTable.",
+	)
+	confProvider.BindPFlag("graphFocus", graphCmd.PersistentFlags().Lookup("focus"))
+	confProvider.BindEnvVar("graphFocus", "CELERITY_CLI_GRAPH_FOCUS")
+
+	graphCmd.PersistentFlags().StringP(
+		"output",
+		"o",
+		"",
+		"The file to write the rendered graph to, the graph is written to stdout by default.",
+	)
+	confProvider.BindPFlag("graphOutputFile", graphCmd.PersistentFlags().Lookup("output"))
+	confProvider.BindEnvVar("graphOutputFile", "CELERITY_CLI_GRAPH_OUTPUT_FILE")
+
+	rootCmd.AddCommand(graphCmd)
+}
+
+func writeGraph(outputFile string, render func(w io.Writer) error) error {
+	if outputFile == "" {
+		return render(os.Stdout)
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return render(file)
+}
+
+// splitList splits a comma-separated flag value into its items,
+// whitespace around items is removed and empty items are dropped.
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(item)
+		if trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return items
+}
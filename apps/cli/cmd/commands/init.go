@@ -1,28 +1,52 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
-	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/scaffold"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/initui"
 	"github.com/newstack-cloud/bluelink/libs/common/core"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
+var errInitCancelled = errors.New("project initialisation was cancelled, no files were created")
+
 func setupInitCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
 	supportedLanguagesStr := strings.Join(
-		core.Map(consts.SupportedLanguages, quote),
+		core.Map(scaffold.Languages, quote),
+		", ",
+	)
+	supportedTemplatesStr := strings.Join(
+		core.Map(scaffold.Templates, quoteTemplate),
 		", ",
 	)
 	initCmd := &cobra.Command{
-		Use:   "init",
+		Use:   "init [directory]",
 		Short: "Initialises a new Celerity project",
 		Long: `Initialises a new Celerity project, this will take you through an interactive set up
-		process but you can also use flags to skip certain prompts.`,
+		process but you can also use flags to skip certain prompts.
+
+		The project is created in the given directory, or the current directory when one is not
+		provided. A blueprint is created from the chosen template along with the CLI config
+		(celerity.config.toml), the deploy target (app.deploy.jsonc) and the deploy configuration
+		that declares the provider for the deploy target (celerity.deploy.json).
+		Projects can be created for the languages that have a runtime image for "celerity dev",
+		which are currently Node.js and Python, and example handlers can be included.
+		JSON blueprints are written to app.blueprint.jsonc.`,
+		Annotations: map[string]string{skipConfigFileAnnotation: "true"},
+		Example: `  celerity init
+  celerity init orders-api --language nodejs --template api --example-handlers
+  celerity init --language python --template workflow --format json --deploy-target gcloud`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			lang, _ := confProvider.GetString("initLanguage")
 			err := validateLanguage(lang, supportedLanguagesStr)
@@ -30,8 +54,90 @@ func setupInitCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
 				return err
 			}
 
-			_, err = tea.NewProgram(initui.NewInitApp(lang)).Run()
-			return err
+			templateValue, _ := confProvider.GetString("initTemplate")
+			if templateValue != "" {
+				if _, err := scaffold.ParseTemplate(templateValue); err != nil {
+					return err
+				}
+			}
+
+			formatValue, _ := confProvider.GetString("initFormat")
+			format, err := scaffold.ParseFormat(formatValue)
+			if err != nil {
+				return err
+			}
+
+			exampleHandlers, exampleHandlersIsDefault := confProvider.GetBool("initExampleHandlers")
+
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			absDir, err := filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+
+			name, _ := confProvider.GetString("initName")
+			if name == "" {
+				name = filepath.Base(absDir)
+			}
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if inTerminal {
+				app := initui.NewInitApp(lang, templateValue, exampleHandlersIsDefault)
+				if !app.Done() {
+					finalModel, err := tea.NewProgram(app).Run()
+					if err != nil {
+						return err
+					}
+
+					finalApp := finalModel.(initui.InitModel)
+					if !finalApp.Done() {
+						// Cancelling the prompts is not a problem with how the command
+						// was used, the error is returned so scripts can tell that
+						// the project was not created.
+						cmd.SilenceUsage = true
+						return errInitCancelled
+					}
+					lang = finalApp.Language
+					templateValue = finalApp.Template
+					if exampleHandlersIsDefault {
+						exampleHandlers = finalApp.ExampleHandlers
+					}
+				}
+			}
+
+			if lang == "" {
+				return errors.New(
+					"a language must be provided with the --language flag " +
+						"when initialising a project in a non-interactive environment",
+				)
+			}
+
+			template := scaffold.TemplateAPI
+			if templateValue != "" {
+				template = scaffold.Template(templateValue)
+			}
+
+			deployTarget, _ := confProvider.GetString("initDeployTarget")
+			force, _ := confProvider.GetBool("initForce")
+			result, err := scaffold.Generate(&scaffold.Options{
+				Dir:             absDir,
+				Name:            name,
+				Language:        lang,
+				Template:        template,
+				Format:          format,
+				DeployTarget:    deployTarget,
+				ExampleHandlers: exampleHandlers,
+				Force:           force,
+			})
+			if err != nil {
+				return err
+			}
+
+			printInitResult(cmd.OutOrStdout(), dir, result)
+			return nil
 		},
 	}
 
@@ -44,16 +150,77 @@ func setupInitCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
 	confProvider.BindPFlag("initLanguage", initCmd.PersistentFlags().Lookup("language"))
 	confProvider.BindEnvVar("initLanguage", "CELERITY_CLI_INIT_LANGUAGE")
 
+	initCmd.PersistentFlags().StringP(
+		"template",
+		"t",
+		"",
+		fmt.Sprintf("The template to create the project from. Can be one of %s.", supportedTemplatesStr),
+	)
+	confProvider.BindPFlag("initTemplate", initCmd.PersistentFlags().Lookup("template"))
+	confProvider.BindEnvVar("initTemplate", "CELERITY_CLI_INIT_TEMPLATE")
+
+	initCmd.PersistentFlags().String(
+		"format",
+		string(scaffold.FormatYAML),
+		"The format of the blueprint file, either \"yaml\" or \"json\".",
+	)
+	confProvider.BindPFlag("initFormat", initCmd.PersistentFlags().Lookup("format"))
+	confProvider.BindEnvVar("initFormat", "CELERITY_CLI_INIT_FORMAT")
+
+	initCmd.PersistentFlags().String(
+		"name",
+		"",
+		"The name of the application, this defaults to the name of the project directory.",
+	)
+	confProvider.BindPFlag("initName", initCmd.PersistentFlags().Lookup("name"))
+	confProvider.BindEnvVar("initName", "CELERITY_CLI_INIT_NAME")
+
+	initCmd.PersistentFlags().String(
+		"deploy-target",
+		"aws",
+		"The deploy target for the application, e.g. \"aws\", \"aws-serverless\", "+
+			"\"gcloud\", \"gcloud-serverless\", \"azure\" or \"azure-serverless\".",
+	)
+	confProvider.BindPFlag("initDeployTarget", initCmd.PersistentFlags().Lookup("deploy-target"))
+	confProvider.BindEnvVar("initDeployTarget", "CELERITY_CLI_INIT_DEPLOY_TARGET")
+
+	initCmd.PersistentFlags().Bool(
+		"example-handlers",
+		false,
+		"Include example handler source files for the handlers in the template.",
+	)
+	confProvider.BindPFlag("initExampleHandlers", initCmd.PersistentFlags().Lookup("example-handlers"))
+	confProvider.BindEnvVar("initExampleHandlers", "CELERITY_CLI_INIT_EXAMPLE_HANDLERS")
+
+	initCmd.PersistentFlags().Bool(
+		"force",
+		false,
+		"Overwrite existing files in the project directory.",
+	)
+	confProvider.BindPFlag("initForce", initCmd.PersistentFlags().Lookup("force"))
+	confProvider.BindEnvVar("initForce", "CELERITY_CLI_INIT_FORCE")
+
 	rootCmd.AddCommand(initCmd)
 }
 
+func printInitResult(out io.Writer, dir string, result *scaffold.Result) {
+	fmt.Fprintf(out, "Created a new Celerity project in %s:\n", dir)
+	for _, file := range result.Files {
+		fmt.Fprintf(out, "  %s\n", file)
+	}
+
+	for _, note := range result.Notes {
+		fmt.Fprintf(out, "\n%s\n", note)
+	}
+}
+
 func validateLanguage(lang string, supportedLanguagesText string) error {
 	if lang == "" {
 		// Empty language is fine, it means the user will have to choose one
 		// in the interactive TUI.
 		return nil
 	}
-	if slices.Contains(consts.SupportedLanguages, lang) {
+	if slices.Contains(scaffold.Languages, lang) {
 		return nil
 	}
 
@@ -67,3 +234,7 @@ func validateLanguage(lang string, supportedLanguagesText string) error {
 func quote(s string, _ int) string {
 	return fmt.Sprintf(`"%s"`, s)
 }
+
+func quoteTemplate(template scaffold.Template, index int) string {
+	return quote(string(template), index)
+}
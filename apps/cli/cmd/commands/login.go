@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/auth"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+)
+
+func setupLoginCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Logs in to the deploy engine",
+		Long: `Logs in to the OAuth2 or OIDC provider that protects the deploy engine
+	and stores the credentials for use by other commands.
+
+	The device-code method is for users, it shows a code to enter in a browser
+	(possibly on another device) to approve the login. The client-credentials method
+	is for machines such as CI pipelines and uses a client ID and secret.
+
+	Credentials are stored in the OS keychain (macOS Keychain or a Secret Service
+	provider on linux), falling back to a file in the user's config directory when a
+	keychain is not available. The file is encrypted with a key stored next to it, so it
+	is no more secure than a plain text file readable only by you, a warning is shown
+	when the file is used.
+
+	Commands that call the deploy engine use the stored credentials and refresh access
+	tokens automatically when they expire, run "celerity logout" to remove the stored
+	credentials.`,
+		Annotations: map[string]string{skipConfigFileAnnotation: "true"},
+		Example: `  celerity login --issuer-url https://auth.example.com --client-id celerity-cli
+  CELERITY_CLI_LOGIN_CLIENT_SECRET=... celerity login --method client-credentials \
+    --issuer-url https://auth.example.com --client-id ci-pipeline`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			methodValue, _ := confProvider.GetString("loginMethod")
+			method, err := auth.ParseMethod(methodValue)
+			if err != nil {
+				return err
+			}
+
+			issuerURL, _ := confProvider.GetString("loginIssuerURL")
+			tokenEndpoint, _ := confProvider.GetString("loginTokenEndpoint")
+			deviceAuthEndpoint, _ := confProvider.GetString("loginDeviceAuthEndpoint")
+			clientID, _ := confProvider.GetString("loginClientID")
+			clientSecret, _ := confProvider.GetString("loginClientSecret")
+			scopes, _ := confProvider.GetString("loginScopes")
+
+			dir, err := auth.DefaultDir()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			credentials, err := auth.Login(
+				cmd.Context(),
+				&auth.LoginOptions{
+					Method:             method,
+					IssuerURL:          issuerURL,
+					TokenEndpoint:      tokenEndpoint,
+					DeviceAuthEndpoint: deviceAuthEndpoint,
+					ClientID:           clientID,
+					ClientSecret:       clientSecret,
+					Scopes:             splitList(scopes),
+				},
+				func(deviceAuth *oauth2.DeviceAuthResponse) {
+					printDeviceCode(out, deviceAuth)
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := auth.NewStore(dir).Save(credentials); err != nil {
+				return fmt.Errorf("storing credentials: %w", err)
+			}
+
+			if auth.StoredInFile(dir) {
+				fmt.Fprintf(
+					cmd.ErrOrStderr(),
+					"Warning: the OS keychain is not available, the credentials are stored in %s "+
+						"with the key used to encrypt them, anyone who can read your files can use them\n",
+					dir,
+				)
+			}
+
+			fmt.Fprintln(out, "Logged in successfully")
+			return nil
+		},
+	}
+
+	loginCmd.PersistentFlags().String(
+		"method",
+		string(auth.DefaultMethod),
+		"The login method to use, either \"device-code\" or \"client-credentials\".",
+	)
+	confProvider.BindPFlag("loginMethod", loginCmd.PersistentFlags().Lookup("method"))
+	confProvider.BindEnvVar("loginMethod", "CELERITY_CLI_LOGIN_METHOD")
+
+	loginCmd.PersistentFlags().String(
+		"issuer-url",
+		"",
+		"The base URL of the OAuth2 or OIDC provider, used to discover "+
+			"the token and device authorization endpoints.",
+	)
+	confProvider.BindPFlag("loginIssuerURL", loginCmd.PersistentFlags().Lookup("issuer-url"))
+	confProvider.BindEnvVar("loginIssuerURL", "CELERITY_CLI_LOGIN_ISSUER_URL")
+
+	loginCmd.PersistentFlags().String(
+		"token-endpoint",
+		"",
+		"The token endpoint of the provider, this takes precedence over a discovered endpoint.",
+	)
+	confProvider.BindPFlag("loginTokenEndpoint", loginCmd.PersistentFlags().Lookup("token-endpoint"))
+	confProvider.BindEnvVar("loginTokenEndpoint", "CELERITY_CLI_LOGIN_TOKEN_ENDPOINT")
+
+	loginCmd.PersistentFlags().String(
+		"device-auth-endpoint",
+		"",
+		"The device authorization endpoint of the provider, "+
+			"this takes precedence over a discovered endpoint.",
+	)
+	confProvider.BindPFlag("loginDeviceAuthEndpoint", loginCmd.PersistentFlags().Lookup("device-auth-endpoint"))
+	confProvider.BindEnvVar("loginDeviceAuthEndpoint", "CELERITY_CLI_LOGIN_DEVICE_AUTH_ENDPOINT")
+
+	loginCmd.PersistentFlags().String(
+		"client-id",
+		"",
+		"The OAuth2 client ID to log in with.",
+	)
+	confProvider.BindPFlag("loginClientID", loginCmd.PersistentFlags().Lookup("client-id"))
+	confProvider.BindEnvVar("loginClientID", "CELERITY_CLI_LOGIN_CLIENT_ID")
+
+	loginCmd.PersistentFlags().String(
+		"client-secret",
+		"",
+		"The OAuth2 client secret for the client-credentials method, "+
+			"prefer the CELERITY_CLI_LOGIN_CLIENT_SECRET environment variable "+
+			"to keep the secret out of your shell history.",
+	)
+	confProvider.BindPFlag("loginClientSecret", loginCmd.PersistentFlags().Lookup("client-secret"))
+	confProvider.BindEnvVar("loginClientSecret", "CELERITY_CLI_LOGIN_CLIENT_SECRET")
+
+	loginCmd.PersistentFlags().String(
+		"scopes",
+		"",
+		"A comma-separated list of scopes to request.",
+	)
+	confProvider.BindPFlag("loginScopes", loginCmd.PersistentFlags().Lookup("scopes"))
+	confProvider.BindEnvVar("loginScopes", "CELERITY_CLI_LOGIN_SCOPES")
+
+	rootCmd.AddCommand(loginCmd)
+}
+
+func printDeviceCode(out io.Writer, deviceAuth *oauth2.DeviceAuthResponse) {
+	if deviceAuth.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "Open the following URL in a browser to log in:\n\n  %s\n\n", deviceAuth.VerificationURIComplete)
+		fmt.Fprintf(out, "Confirm that the code shown matches: %s\n", deviceAuth.UserCode)
+	} else {
+		fmt.Fprintf(out, "Open the following URL in a browser to log in:\n\n  %s\n\n", deviceAuth.VerificationURI)
+		fmt.Fprintf(out, "Enter the code: %s\n", deviceAuth.UserCode)
+	}
+	fmt.Fprintln(out, "Waiting for the login to be approved...")
+}
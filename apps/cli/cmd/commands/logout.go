@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+func setupLogoutCommand(rootCmd *cobra.Command) {
+	logoutCmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Logs out of the deploy engine",
+		Long: `Removes the credentials stored by "celerity login" from the OS keychain
+	and the credentials file, commands then use an API key to call the deploy engine.`,
+		Annotations: map[string]string{skipConfigFileAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := auth.DefaultDir()
+			if err != nil {
+				return err
+			}
+
+			if err := auth.NewStore(dir).Delete(); err != nil {
+				return fmt.Errorf("removing credentials: %w", err)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Logged out")
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(logoutCmd)
+}
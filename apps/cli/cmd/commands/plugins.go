@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+func setupPluginsCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	pluginsCmd := &cobra.Command{
+		Use:   "plugins",
+		Short: "Manage provider and transformer plugins for the deploy engine",
+		Long: `Install, list, upgrade and remove provider and transformer plugins for the
+deploy engine running on the same machine as the CLI.
+
+Plugins are identified by "[host/]namespace/name", plugins without a host are
+installed from the default plugin registry (` + plugins.DefaultRegistryHost + `) and plugins with the
+"github.com" host are installed from the releases of the GitHub repository,
+e.g. "github.com/newstack-cloud/bluelink-provider-aws".
+
+  celerity plugins install   Install plugins
+  celerity plugins list      List installed plugins
+  celerity plugins upgrade   Upgrade installed plugins to their latest versions
+  celerity plugins remove    Remove installed plugins`,
+		Annotations: map[string]string{skipConfigFileAnnotation: "true"},
+	}
+
+	pluginsCmd.PersistentFlags().String(
+		"plugin-dir",
+		"",
+		"The directory plugins are installed to, this defaults to the first directory in "+
+			plugins.PluginPathEnvVar+" or ~/.bluelink/engine/plugins/bin when it is not set.",
+	)
+	confProvider.BindPFlag("pluginsDir", pluginsCmd.PersistentFlags().Lookup("plugin-dir"))
+	confProvider.BindEnvVar("pluginsDir", "CELERITY_CLI_PLUGINS_DIR")
+
+	pluginsCmd.PersistentFlags().String(
+		"public-key",
+		"",
+		"A base64 encoded ed25519 public key used to verify the signatures of plugin releases, "+
+			"when set, only signed releases can be installed.",
+	)
+	confProvider.BindPFlag("pluginsPublicKey", pluginsCmd.PersistentFlags().Lookup("public-key"))
+	confProvider.BindEnvVar("pluginsPublicKey", "CELERITY_CLI_PLUGINS_PUBLIC_KEY")
+
+	setupPluginsInstallCommand(pluginsCmd, confProvider)
+	setupPluginsListCommand(pluginsCmd, confProvider)
+	setupPluginsUpgradeCommand(pluginsCmd, confProvider)
+	setupPluginsRemoveCommand(pluginsCmd, confProvider)
+
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+func pluginsLayout(confProvider *config.Provider) (plugins.Layout, error) {
+	dir, _ := confProvider.GetString("pluginsDir")
+	return pluginsLayoutForDir(dir)
+}
+
+// pluginsLayoutForDir creates the layout for a plugin directory,
+// falling back to the default plugin directory when dir is empty.
+func pluginsLayoutForDir(dir string) (plugins.Layout, error) {
+	if dir == "" {
+		defaultDir, err := plugins.DefaultDir()
+		if err != nil {
+			return plugins.Layout{}, err
+		}
+		dir = defaultDir
+	}
+
+	return plugins.Layout{Dir: dir}, nil
+}
+
+func pluginsInstaller(confProvider *config.Provider) (*plugins.Installer, error) {
+	layout, err := pluginsLayout(confProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, _ := confProvider.GetString("pluginsPublicKey")
+	return pluginsInstallerForLayout(layout, publicKey)
+}
+
+// pluginsInstallerForLayout creates an installer for the plugins in a layout,
+// verifying release signatures with the base64 encoded public key when set.
+func pluginsInstallerForLayout(layout plugins.Layout, publicKeyValue string) (*plugins.Installer, error) {
+	publicKey, err := parsePluginsPublicKey(publicKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return plugins.NewInstaller(layout, plugins.InstallerOptions{
+		Platform: plugins.Platform{
+			OS:   runtime.GOOS,
+			Arch: runtime.GOARCH,
+		},
+		PublicKey: publicKey,
+	}), nil
+}
+
+func parsePluginsPublicKey(value string) (ed25519.PublicKey, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("the public key must be a base64 encoded ed25519 public key")
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// warnUnverifiedPlugin warns that an installed plugin release was not
+// authenticated when the installer is not configured with a public key
+// to verify release signatures.
+func warnUnverifiedPlugin(out io.Writer, installer *plugins.Installer, installed *plugins.Installed) {
+	if installer.VerifiesSignatures() {
+		return
+	}
+
+	fmt.Fprintf(
+		out,
+		"Warning: the signature of %s %s was not verified as no public key is configured, "+
+			"the release was only checked against checksums from the same source, "+
+			"use --public-key to verify plugin releases\n",
+		installed.ID,
+		installed.Version,
+	)
+}
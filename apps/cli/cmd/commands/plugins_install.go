@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+func setupPluginsInstallCommand(pluginsCmd *cobra.Command, confProvider *config.Provider) {
+	installCmd := &cobra.Command{
+		Use:   "install <plugin-id[@version]>...",
+		Short: "Installs plugins",
+		Long: `Downloads and installs plugins into the plugin directory, the latest version
+is installed when a version is not provided.
+
+The archive for each release is verified against the checksums published with the
+release before the plugin is installed. The checksums come from the same source as
+the archive, so they do not detect a compromised registry or release on their own,
+set --public-key to also verify the signature of the checksums. A warning is shown
+for each plugin installed without a verified signature.`,
+		Example: `  celerity plugins install bluelink/aws
+  celerity plugins install bluelink/aws@1.2.0 --type provider
+  celerity plugins install github.com/newstack-cloud/celerity-transformer --type transformer`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			typeValue, _ := confProvider.GetString("pluginsInstallType")
+			pluginType, err := plugins.ParseType(typeValue)
+			if err != nil {
+				return err
+			}
+			force, _ := confProvider.GetBool("pluginsInstallForce")
+
+			installer, err := pluginsInstaller(confProvider)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, arg := range args {
+				id, version, err := plugins.ParseID(arg)
+				if err != nil {
+					return err
+				}
+
+				installed, err := installer.Install(cmd.Context(), pluginType, id, version, force)
+				if errors.Is(err, plugins.ErrAlreadyInstalled) {
+					fmt.Fprintf(out, "%s %s is already installed, use --force to re-install it\n", id, installed.Version)
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to install %s: %w", id, err)
+				}
+
+				warnUnverifiedPlugin(cmd.ErrOrStderr(), installer, installed)
+				fmt.Fprintf(out, "Installed %s %s to %s\n", id, installed.Version, installed.Path)
+			}
+
+			return nil
+		},
+	}
+
+	installCmd.Flags().String(
+		"type",
+		string(plugins.TypeProvider),
+		fmt.Sprintf("The type of the plugins, either %q or %q.", plugins.TypeProvider, plugins.TypeTransformer),
+	)
+	confProvider.BindPFlag("pluginsInstallType", installCmd.Flags().Lookup("type"))
+	confProvider.BindEnvVar("pluginsInstallType", "CELERITY_CLI_PLUGINS_INSTALL_TYPE")
+
+	installCmd.Flags().Bool(
+		"force",
+		false,
+		"Re-install plugin versions that are already installed.",
+	)
+	confProvider.BindPFlag("pluginsInstallForce", installCmd.Flags().Lookup("force"))
+
+	pluginsCmd.AddCommand(installCmd)
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func setupPluginsListCommand(pluginsCmd *cobra.Command, confProvider *config.Provider) {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists installed plugins",
+		Long:  `Lists the plugins installed in the plugin directory along with their versions.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			layout, err := pluginsLayout(confProvider)
+			if err != nil {
+				return err
+			}
+
+			installed, err := layout.List()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(installed) == 0 {
+				fmt.Fprintf(out, "No plugins are installed in %s\n", layout.Dir)
+				return nil
+			}
+
+			writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "TYPE\tPLUGIN\tVERSION")
+			for _, plugin := range installed {
+				fmt.Fprintf(writer, "%s\t%s\t%s\n", plugin.Type, plugin.ID, plugin.Version)
+			}
+			return writer.Flush()
+		},
+	}
+
+	pluginsCmd.AddCommand(listCmd)
+}
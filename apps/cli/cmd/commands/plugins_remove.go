@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+func setupPluginsRemoveCommand(pluginsCmd *cobra.Command, confProvider *config.Provider) {
+	removeCmd := &cobra.Command{
+		Use:   "remove <plugin-id[@version]>...",
+		Short: "Removes installed plugins",
+		Long: `Removes plugins from the plugin directory, all installed versions of a plugin
+are removed unless a version is provided.`,
+		Example: `  celerity plugins remove bluelink/aws
+  celerity plugins remove bluelink/aws@1.1.0`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			layout, err := pluginsLayout(confProvider)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, arg := range args {
+				id, version, err := plugins.ParseID(arg)
+				if err != nil {
+					return err
+				}
+
+				installed, err := layout.Find(id)
+				if err != nil {
+					return err
+				}
+
+				removed := 0
+				for _, plugin := range installed {
+					if version != "" && plugin.Version != version {
+						continue
+					}
+					if err := layout.Remove(plugin); err != nil {
+						return err
+					}
+					fmt.Fprintf(out, "Removed %s %s\n", plugin.ID, plugin.Version)
+					removed += 1
+				}
+
+				if removed == 0 {
+					return fmt.Errorf("%s is not installed", arg)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	pluginsCmd.AddCommand(removeCmd)
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+func setupPluginsUpgradeCommand(pluginsCmd *cobra.Command, confProvider *config.Provider) {
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade [plugin-id]...",
+		Short: "Upgrades installed plugins to their latest versions",
+		Long: `Upgrades the given plugins, or all installed plugins when none are provided,
+to their latest versions. The older versions that are replaced are removed once
+the latest version has been installed, versions that are newer than the latest
+release are left in place.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			layout, err := pluginsLayout(confProvider)
+			if err != nil {
+				return err
+			}
+
+			targets, err := pluginsToUpgrade(layout, args)
+			if err != nil {
+				return err
+			}
+
+			installer, err := pluginsInstaller(confProvider)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(targets) == 0 {
+				fmt.Fprintf(out, "No plugins are installed in %s\n", layout.Dir)
+				return nil
+			}
+
+			for _, target := range targets {
+				id := target.ID
+				result, err := installer.Upgrade(cmd.Context(), target.Type, id)
+				if err != nil {
+					return fmt.Errorf("failed to upgrade %s: %w", id, err)
+				}
+
+				if result.UpToDate {
+					fmt.Fprintf(out, "%s is up to date (%s)\n", id, result.Installed.Version)
+					continue
+				}
+
+				warnUnverifiedPlugin(cmd.ErrOrStderr(), installer, result.Installed)
+				fmt.Fprintf(out, "Upgraded %s to %s\n", id, result.Installed.Version)
+				for _, removed := range result.Removed {
+					fmt.Fprintf(out, "  removed %s\n", removed.Version)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	pluginsCmd.AddCommand(upgradeCmd)
+}
+
+// pluginToUpgrade is an installed plugin of a specific type,
+// the same plugin ID can be installed as both a provider and a transformer.
+type pluginToUpgrade struct {
+	Type plugins.Type
+	ID   plugins.ID
+}
+
+func pluginsToUpgrade(layout plugins.Layout, args []string) ([]*pluginToUpgrade, error) {
+	installed, err := layout.List()
+	if err != nil {
+		return nil, err
+	}
+
+	requested := map[plugins.ID]bool{}
+	requestedIDs := []plugins.ID{}
+	for _, arg := range args {
+		id, version, err := plugins.ParseID(arg)
+		if err != nil {
+			return nil, err
+		}
+		if version != "" {
+			return nil, fmt.Errorf(
+				"plugins are always upgraded to their latest version, remove the version from %q",
+				arg,
+			)
+		}
+		requested[id] = true
+		requestedIDs = append(requestedIDs, id)
+	}
+
+	targets := []*pluginToUpgrade{}
+	seen := map[pluginToUpgrade]bool{}
+	for _, plugin := range installed {
+		target := pluginToUpgrade{Type: plugin.Type, ID: plugin.ID}
+		if seen[target] || (len(requested) > 0 && !requested[plugin.ID]) {
+			continue
+		}
+		seen[target] = true
+		targets = append(targets, &target)
+	}
+
+	for _, id := range requestedIDs {
+		if !slices.ContainsFunc(targets, func(target *pluginToUpgrade) bool {
+			return target.ID == id
+		}) {
+			return nil, fmt.Errorf("%w: %s", plugins.ErrNotInstalled, id)
+		}
+	}
+
+	return targets, nil
+}
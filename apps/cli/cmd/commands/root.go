@@ -24,12 +24,13 @@ func NewRootCmd() *cobra.Command {
 This CLI validates, builds, and deploys celerity applications
 along with blueprints used for Infrastructure as Code.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if err := confProvider.LoadConfigFile(configFile); err != nil {
+			err := confProvider.LoadConfigFile(configFile)
+			if err != nil && !canSkipConfigFile(cmd, err) {
 				return err
 			}
 
 			connectProtocol, _ := confProvider.GetString("connectProtocol")
-			err := validateConnectProtocol(connectProtocol)
+			err = validateConnectProtocol(connectProtocol)
 			if err != nil {
 				return err
 			}
@@ -93,6 +94,14 @@ along with blueprints used for Infrastructure as Code.`,
 	setupInitCommand(rootCmd, confProvider)
 	setupValidateCommand(rootCmd, confProvider)
 	setupDevCommand(rootCmd, confProvider)
+	setupConsoleCommand(rootCmd, confProvider)
+	setupGraphCommand(rootCmd, confProvider)
+	setupDeployCommand(rootCmd, confProvider)
+	setupDriftCommand(rootCmd, confProvider)
+	setupLoginCommand(rootCmd, confProvider)
+	setupLogoutCommand(rootCmd)
+	setupPluginsCommand(rootCmd, confProvider)
+	setupDocsCommand(rootCmd, confProvider)
 
 	return rootCmd
 }
@@ -139,3 +148,25 @@ func OnInitialise() {
 		fmt.Println(asciiArt)
 	}
 }
+
+// skipConfigFileAnnotation is set on commands that do not need project config
+// so they can run outside of a project that has a config file,
+// subcommands inherit the annotation from their parent commands.
+const skipConfigFileAnnotation = "skipConfigFile"
+
+// canSkipConfigFile determines whether a command can run without
+// a config file, e.g. the init command creates the default config file
+// so it can not expect it to exist already.
+func canSkipConfigFile(cmd *cobra.Command, err error) bool {
+	if !os.IsNotExist(err) {
+		return false
+	}
+
+	for current := cmd; current != nil; current = current.Parent() {
+		if current.Annotations[skipConfigFileAnnotation] == "true" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,9 +8,10 @@ import (
 
 func setupVersionCommand(rootCmd *cobra.Command) {
 	versionCmd := &cobra.Command{
-		Use:   "version",
-		Short: "Print the version number of Celerity CLI",
-		Long:  `All software has versions. This is Celerity CLI's`,
+		Use:         "version",
+		Short:       "Print the version number of Celerity CLI",
+		Long:        `All software has versions. This is Celerity CLI's`,
+		Annotations: map[string]string{skipConfigFileAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			fmt.Println("Celerity CLI v0.1")
 		},
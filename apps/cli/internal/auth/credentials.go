@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+// Method is the OAuth2 flow used to log in to the deploy engine.
+type Method string
+
+const (
+	// MethodDeviceCode is used for interactive logins where the user
+	// approves the login in a browser, possibly on another device.
+	MethodDeviceCode Method = "device-code"
+	// MethodClientCredentials is used for machine logins with
+	// a client ID and secret, e.g. in CI pipelines.
+	MethodClientCredentials Method = "client-credentials"
+	// DefaultMethod is the login method used when one is not specified.
+	DefaultMethod = MethodDeviceCode
+)
+
+// ParseMethod parses a login method from its name.
+func ParseMethod(value string) (Method, error) {
+	switch Method(value) {
+	case MethodDeviceCode, MethodClientCredentials:
+		return Method(value), nil
+	}
+
+	return "", fmt.Errorf(
+		"unsupported login method %q, expected one of %q or %q",
+		value,
+		MethodDeviceCode,
+		MethodClientCredentials,
+	)
+}
+
+// ErrNotLoggedIn is returned when there are no stored credentials.
+var ErrNotLoggedIn = errors.New("not logged in, run \"celerity login\" to log in")
+
+// Credentials holds everything needed to obtain and refresh access tokens
+// for the deploy engine after a successful login.
+type Credentials struct {
+	Method             Method        `json:"method"`
+	TokenEndpoint      string        `json:"tokenEndpoint"`
+	DeviceAuthEndpoint string        `json:"deviceAuthEndpoint,omitempty"`
+	ClientID           string        `json:"clientId"`
+	ClientSecret       string        `json:"clientSecret,omitempty"`
+	Scopes             []string      `json:"scopes,omitempty"`
+	Token              *oauth2.Token `json:"token,omitempty"`
+}
+
+func (c *Credentials) oauth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Scopes:       c.Scopes,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:      c.TokenEndpoint,
+			DeviceAuthURL: c.DeviceAuthEndpoint,
+		},
+	}
+}
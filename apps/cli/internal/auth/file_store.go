@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	credentialsFileName = "credentials.enc"
+	keyFileName         = "credentials.key"
+	keySize             = 32
+)
+
+// EncryptedFileStore stores credentials in a file encrypted with AES-256-GCM.
+// The encryption key is generated on first use and stored in the same
+// directory as the credentials, so anyone who can read the user's files can
+// decrypt them and the store is no more secure than a plain text file
+// readable only by the current user.
+// The encryption only stops the tokens from showing up when the file
+// contents are viewed or searched, it is used as a fallback when the OS
+// keychain is not available and users should be warned when it is used.
+type EncryptedFileStore struct {
+	dir string
+}
+
+// NewEncryptedFileStore creates a credentials store that writes
+// to an encrypted file in the given directory.
+func NewEncryptedFileStore(dir string) *EncryptedFileStore {
+	return &EncryptedFileStore{dir: dir}
+}
+
+func (s *EncryptedFileStore) Load() (*Credentials, error) {
+	ciphertext, err := os.ReadFile(filepath.Join(s.dir, credentialsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotLoggedIn
+		}
+		return nil, fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	key, err := os.ReadFile(filepath.Join(s.dir, keyFileName))
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("credentials file is corrupted, run \"celerity login\" to log in again")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	data, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt credentials, run \"celerity login\" to log in again")
+	}
+
+	credentials := &Credentials{}
+	if err := json.Unmarshal(data, credentials); err != nil {
+		return nil, fmt.Errorf("parsing credentials file: %w", err)
+	}
+
+	return credentials, nil
+}
+
+func (s *EncryptedFileStore) Save(credentials *Credentials) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("creating credentials directory: %w", err)
+	}
+
+	key, err := s.loadOrCreateKey()
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, data, nil)
+	return writeFileAtomic(filepath.Join(s.dir, credentialsFileName), ciphertext)
+}
+
+func (s *EncryptedFileStore) Delete() error {
+	for _, name := range []string{credentialsFileName, keyFileName} {
+		err := os.Remove(filepath.Join(s.dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *EncryptedFileStore) loadOrCreateKey() ([]byte, error) {
+	keyPath := filepath.Join(s.dir, keyFileName)
+	key, err := os.ReadFile(keyPath)
+	if err == nil && len(key) == keySize {
+		return key, nil
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading credentials key: %w", err)
+	}
+
+	key = make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	if err := writeFileAtomic(keyPath, key); err != nil {
+		return nil, fmt.Errorf("writing credentials key: %w", err)
+	}
+
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	keychainService = "celerity-cli"
+	keychainAccount = "credentials"
+	keychainLabel   = "Celerity CLI credentials"
+	// Exit code used by the macOS security tool when an item
+	// can not be found in the keychain.
+	macOSItemNotFoundExitCode = 44
+)
+
+var errKeychainUnavailable = errors.New("OS keychain is not available")
+
+// commandRunner runs an external command writing stdin to the
+// process and returning the standard output.
+type commandRunner func(stdin string, name string, args ...string) ([]byte, error)
+
+func runCommand(stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	return cmd.Output()
+}
+
+// keychainStore stores credentials in the OS keychain through the
+// command line tools that ship with the OS, the credentials are
+// stored as a base64 encoded JSON document.
+// Credentials are always passed to the tools through stdin so they
+// do not show up in the process list.
+type keychainStore struct {
+	run  commandRunner
+	goos string
+}
+
+func newKeychainStore(run commandRunner) *keychainStore {
+	return &keychainStore{
+		run:  run,
+		goos: runtime.GOOS,
+	}
+}
+
+func (s *keychainStore) Load() (*Credentials, error) {
+	output, err := s.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding credentials from the OS keychain: %w", err)
+	}
+
+	credentials := &Credentials{}
+	if err := json.Unmarshal(data, credentials); err != nil {
+		return nil, fmt.Errorf("parsing credentials from the OS keychain: %w", err)
+	}
+
+	return credentials, nil
+}
+
+func (s *keychainStore) lookup() ([]byte, error) {
+	switch s.goos {
+	case "darwin":
+		output, err := s.run(
+			"",
+			"security", "find-generic-password",
+			"-s", keychainService,
+			"-a", keychainAccount,
+			"-w",
+		)
+		return output, s.macOSError(err)
+	case "linux":
+		output, err := s.run(
+			"",
+			"secret-tool", "lookup",
+			"service", keychainService,
+			"account", keychainAccount,
+		)
+		return output, s.linuxError(err)
+	}
+
+	return nil, errKeychainUnavailable
+}
+
+func (s *keychainStore) Save(credentials *Credentials) error {
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	switch s.goos {
+	case "darwin":
+		// The interactive mode of the security tool reads commands from stdin,
+		// -X takes the password as a hex encoded string.
+		_, err := s.run(
+			fmt.Sprintf(
+				"add-generic-password -U -s %s -a %s -l %q -X %s\n",
+				keychainService,
+				keychainAccount,
+				keychainLabel,
+				hex.EncodeToString([]byte(encoded)),
+			),
+			"security", "-i",
+		)
+		return unavailableOnError(err)
+	case "linux":
+		_, err := s.run(
+			encoded,
+			"secret-tool", "store",
+			"--label", keychainLabel,
+			"service", keychainService,
+			"account", keychainAccount,
+		)
+		return unavailableOnError(err)
+	}
+
+	return errKeychainUnavailable
+}
+
+func (s *keychainStore) Delete() error {
+	switch s.goos {
+	case "darwin":
+		_, err := s.run(
+			"",
+			"security", "delete-generic-password",
+			"-s", keychainService,
+			"-a", keychainAccount,
+		)
+		return ignoreNotLoggedIn(s.macOSError(err))
+	case "linux":
+		_, err := s.run(
+			"",
+			"secret-tool", "clear",
+			"service", keychainService,
+			"account", keychainAccount,
+		)
+		return ignoreNotLoggedIn(s.linuxError(err))
+	}
+
+	return errKeychainUnavailable
+}
+
+func (s *keychainStore) macOSError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	exitErr := &exec.ExitError{}
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == macOSItemNotFoundExitCode {
+		return ErrNotLoggedIn
+	}
+
+	return errKeychainUnavailable
+}
+
+func (s *keychainStore) linuxError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// secret-tool exits with a non-zero code without any error output
+	// when there is no matching item, any other failure means there is
+	// no Secret Service provider available.
+	exitErr := &exec.ExitError{}
+	if errors.As(err, &exitErr) && len(strings.TrimSpace(string(exitErr.Stderr))) == 0 {
+		return ErrNotLoggedIn
+	}
+
+	return errKeychainUnavailable
+}
+
+func unavailableOnError(err error) error {
+	if err != nil {
+		return errKeychainUnavailable
+	}
+
+	return nil
+}
+
+func ignoreNotLoggedIn(err error) error {
+	if errors.Is(err, ErrNotLoggedIn) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// LoginOptions holds the configuration for logging in to the
+// OAuth2 or OIDC provider that protects the deploy engine.
+type LoginOptions struct {
+	Method Method
+	// IssuerURL is the base URL of the provider used to discover
+	// endpoints from `/.well-known/openid-configuration` or
+	// `/.well-known/oauth-authorization-server` when they are not set explicitly.
+	IssuerURL          string
+	TokenEndpoint      string
+	DeviceAuthEndpoint string
+	ClientID           string
+	ClientSecret       string
+	Scopes             []string
+	// HTTPClient is used for requests to the provider,
+	// http.DefaultClient is used when not set.
+	HTTPClient *http.Client
+}
+
+var discoveryPaths = []string{
+	"/.well-known/openid-configuration",
+	"/.well-known/oauth-authorization-server",
+}
+
+type providerMetadata struct {
+	TokenEndpoint      string `json:"token_endpoint"`
+	DeviceAuthEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// Login carries out the login flow for the configured method and returns
+// the credentials to be stored.
+// For the device code flow, onDeviceCode is called with the verification URL
+// and user code that must be presented to the user before polling for a token.
+func Login(
+	ctx context.Context,
+	opts *LoginOptions,
+	onDeviceCode func(*oauth2.DeviceAuthResponse),
+) (*Credentials, error) {
+	if opts.ClientID == "" {
+		return nil, errors.New("a client ID must be provided to log in")
+	}
+
+	if err := resolveEndpoints(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	switch opts.Method {
+	case MethodDeviceCode:
+		return loginWithDeviceCode(ctx, opts, onDeviceCode)
+	case MethodClientCredentials:
+		return loginWithClientCredentials(ctx, opts)
+	}
+
+	_, err := ParseMethod(string(opts.Method))
+	return nil, err
+}
+
+func loginWithDeviceCode(
+	ctx context.Context,
+	opts *LoginOptions,
+	onDeviceCode func(*oauth2.DeviceAuthResponse),
+) (*Credentials, error) {
+	if opts.DeviceAuthEndpoint == "" {
+		return nil, errors.New(
+			"the provider does not advertise a device authorization endpoint, " +
+				"set one explicitly or log in with the client-credentials method",
+		)
+	}
+
+	credentials := &Credentials{
+		Method:             MethodDeviceCode,
+		TokenEndpoint:      opts.TokenEndpoint,
+		DeviceAuthEndpoint: opts.DeviceAuthEndpoint,
+		ClientID:           opts.ClientID,
+		ClientSecret:       opts.ClientSecret,
+		Scopes:             opts.Scopes,
+	}
+	config := credentials.oauth2Config()
+	ctx = withHTTPClient(ctx, opts.HTTPClient)
+
+	deviceAuth, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting device authorization: %w", err)
+	}
+
+	if onDeviceCode != nil {
+		onDeviceCode(deviceAuth)
+	}
+
+	token, err := config.DeviceAccessToken(ctx, deviceAuth)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for device authorization: %w", err)
+	}
+
+	credentials.Token = token
+	return credentials, nil
+}
+
+func loginWithClientCredentials(ctx context.Context, opts *LoginOptions) (*Credentials, error) {
+	if opts.ClientSecret == "" {
+		return nil, errors.New("a client secret must be provided to log in with client credentials")
+	}
+
+	credentials := &Credentials{
+		Method:        MethodClientCredentials,
+		TokenEndpoint: opts.TokenEndpoint,
+		ClientID:      opts.ClientID,
+		ClientSecret:  opts.ClientSecret,
+		Scopes:        opts.Scopes,
+	}
+
+	// Fetch a token up front to verify the client credentials before storing them.
+	token, err := clientCredentialsConfig(credentials).Token(withHTTPClient(ctx, opts.HTTPClient))
+	if err != nil {
+		return nil, fmt.Errorf("verifying client credentials: %w", err)
+	}
+
+	credentials.Token = token
+	return credentials, nil
+}
+
+func resolveEndpoints(ctx context.Context, opts *LoginOptions) error {
+	needsDeviceAuth := opts.Method == MethodDeviceCode && opts.DeviceAuthEndpoint == ""
+	if opts.TokenEndpoint != "" && !needsDeviceAuth {
+		return nil
+	}
+
+	if opts.IssuerURL == "" {
+		return errors.New("either an issuer URL or a token endpoint must be provided to log in")
+	}
+
+	metadata, err := discoverEndpoints(ctx, opts.IssuerURL, httpClient(opts.HTTPClient))
+	if err != nil {
+		return err
+	}
+
+	if opts.TokenEndpoint == "" {
+		opts.TokenEndpoint = metadata.TokenEndpoint
+	}
+	if opts.DeviceAuthEndpoint == "" {
+		opts.DeviceAuthEndpoint = metadata.DeviceAuthEndpoint
+	}
+
+	if opts.TokenEndpoint == "" {
+		return fmt.Errorf("the provider at %s does not advertise a token endpoint", opts.IssuerURL)
+	}
+
+	return nil
+}
+
+func discoverEndpoints(ctx context.Context, issuerURL string, client *http.Client) (*providerMetadata, error) {
+	baseURL := strings.TrimSuffix(issuerURL, "/")
+	for _, path := range discoveryPaths {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching provider metadata: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			continue
+		}
+
+		metadata := &providerMetadata{}
+		err = json.NewDecoder(resp.Body).Decode(metadata)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing provider metadata: %w", err)
+		}
+
+		return metadata, nil
+	}
+
+	return nil, fmt.Errorf("no provider metadata found for %s", issuerURL)
+}
+
+func clientCredentialsConfig(credentials *Credentials) *clientcredentials.Config {
+	return &clientcredentials.Config{
+		ClientID:     credentials.ClientID,
+		ClientSecret: credentials.ClientSecret,
+		TokenURL:     credentials.TokenEndpoint,
+		Scopes:       credentials.Scopes,
+	}
+}
+
+func withHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	if client == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+func httpClient(client *http.Client) *http.Client {
+	if client == nil {
+		return http.DefaultClient
+	}
+
+	return client
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/oauth2"
+)
+
+type LoginTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	provider *fakeProvider
+}
+
+func TestLoginTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginTestSuite))
+}
+
+func (s *LoginTestSuite) SetupTest() {
+	s.provider = &fakeProvider{}
+	s.server = httptest.NewServer(s.provider.handler())
+	s.provider.baseURL = s.server.URL
+}
+
+func (s *LoginTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *LoginTestSuite) Test_client_credentials_login_discovers_token_endpoint() {
+	credentials, err := Login(context.Background(), &LoginOptions{
+		Method:       MethodClientCredentials,
+		IssuerURL:    s.server.URL,
+		ClientID:     "ci-pipeline",
+		ClientSecret: "secret",
+	}, nil)
+	s.Require().NoError(err)
+
+	s.Assert().Equal(MethodClientCredentials, credentials.Method)
+	s.Assert().Equal(s.server.URL+"/token", credentials.TokenEndpoint)
+	s.Assert().Equal("secret", credentials.ClientSecret)
+	s.Assert().Equal("access-token-1", credentials.Token.AccessToken)
+}
+
+func (s *LoginTestSuite) Test_client_credentials_login_requires_secret() {
+	_, err := Login(context.Background(), &LoginOptions{
+		Method:        MethodClientCredentials,
+		TokenEndpoint: s.server.URL + "/token",
+		ClientID:      "ci-pipeline",
+	}, nil)
+	s.Assert().ErrorContains(err, "a client secret must be provided")
+}
+
+func (s *LoginTestSuite) Test_login_requires_issuer_or_endpoint() {
+	_, err := Login(context.Background(), &LoginOptions{
+		Method:   MethodDeviceCode,
+		ClientID: "celerity-cli",
+	}, nil)
+	s.Assert().ErrorContains(err, "either an issuer URL or a token endpoint must be provided")
+}
+
+func (s *LoginTestSuite) Test_device_code_login_polls_until_approved() {
+	s.provider.pendingPolls = 1
+
+	var shownCode string
+	credentials, err := Login(context.Background(), &LoginOptions{
+		Method:    MethodDeviceCode,
+		IssuerURL: s.server.URL,
+		ClientID:  "celerity-cli",
+	}, func(deviceAuth *oauth2.DeviceAuthResponse) {
+		shownCode = deviceAuth.UserCode
+	})
+	s.Require().NoError(err)
+
+	s.Assert().Equal("ABCD-EFGH", shownCode)
+	s.Assert().Equal(MethodDeviceCode, credentials.Method)
+	s.Assert().Equal(s.server.URL+"/device", credentials.DeviceAuthEndpoint)
+	s.Assert().Equal("refresh-token", credentials.Token.RefreshToken)
+}
+
+func (s *LoginTestSuite) Test_token_source_refreshes_and_persists_expired_token() {
+	store := NewEncryptedFileStore(s.T().TempDir())
+	credentials := &Credentials{
+		Method:        MethodDeviceCode,
+		TokenEndpoint: s.server.URL + "/token",
+		ClientID:      "celerity-cli",
+		Token: &oauth2.Token{
+			AccessToken:  "expired-token",
+			RefreshToken: "refresh-token",
+			Expiry:       time.Now().Add(-time.Hour),
+		},
+	}
+
+	token, err := NewTokenSource(context.Background(), store, credentials, nil).Token()
+	s.Require().NoError(err)
+	s.Assert().Equal("access-token-1", token.AccessToken)
+
+	stored, err := store.Load()
+	s.Require().NoError(err)
+	s.Assert().Equal("access-token-1", stored.Token.AccessToken)
+	s.Assert().Equal("refresh_token", s.provider.lastGrantType)
+}
+
+// fakeProvider is a minimal OAuth2 provider supporting discovery,
+// device authorization and the token endpoint.
+type fakeProvider struct {
+	mu            sync.Mutex
+	baseURL       string
+	pendingPolls  int
+	tokenCount    int
+	lastGrantType string
+}
+
+func (p *fakeProvider) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"token_endpoint":                p.baseURL + "/token",
+			"device_authorization_endpoint": p.baseURL + "/device",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": p.baseURL + "/activate",
+			"expires_in":       60,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.lastGrantType = r.FormValue("grant_type")
+		if p.lastGrantType == "urn:ietf:params:oauth:grant-type:device_code" && p.pendingPolls > 0 {
+			p.pendingPolls -= 1
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "authorization_pending"})
+			return
+		}
+
+		p.tokenCount += 1
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token":  fmt.Sprintf("access-token-%d", p.tokenCount),
+			"refresh_token": "refresh-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
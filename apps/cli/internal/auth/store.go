@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const loginMarkerFileName = "login"
+
+// Locations recorded in the login marker file.
+const (
+	locationKeychain = "keychain"
+	locationFile     = "file"
+)
+
+// Store persists the credentials of the logged in user or machine.
+type Store interface {
+	// Load retrieves the stored credentials,
+	// returning ErrNotLoggedIn when there are none.
+	Load() (*Credentials, error)
+	// Save stores the credentials, replacing any existing credentials.
+	Save(credentials *Credentials) error
+	// Delete removes any stored credentials.
+	Delete() error
+}
+
+// DefaultDir returns the directory used for the login marker file and
+// the encrypted credentials file when the OS keychain is not available.
+func DefaultDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "celerity"), nil
+}
+
+// NewStore creates a credentials store that uses the OS keychain
+// (macOS Keychain or a Secret Service provider on linux),
+// falling back to a file in the given directory when
+// the keychain is not available.
+func NewStore(dir string) Store {
+	return &fallbackStore{
+		primary:   newKeychainStore(runCommand),
+		secondary: NewEncryptedFileStore(dir),
+		dir:       dir,
+	}
+}
+
+// StoredInFile reports whether the credentials of the logged in user or
+// machine are held in the file store instead of the OS keychain,
+// see EncryptedFileStore for why this should be surfaced to the user.
+func StoredInFile(dir string) bool {
+	location, err := readLoginMarker(dir)
+	return err == nil && location == locationFile
+}
+
+// fallbackStore uses the primary store when it is available
+// and the secondary store otherwise.
+// A login marker file in the directory records the store that holds the
+// credentials, so loading credentials when the user has not logged in
+// does not need to probe the keychain tools.
+type fallbackStore struct {
+	primary   Store
+	secondary Store
+	dir       string
+}
+
+func (s *fallbackStore) Load() (*Credentials, error) {
+	location, err := readLoginMarker(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == locationFile {
+		return s.secondary.Load()
+	}
+
+	return s.primary.Load()
+}
+
+func (s *fallbackStore) Save(credentials *Credentials) error {
+	err := s.primary.Save(credentials)
+	if err == nil {
+		// Make sure credentials from a previous login that fell back to the
+		// file store are not left behind.
+		if err := s.secondary.Delete(); err != nil {
+			return err
+		}
+		return writeLoginMarker(s.dir, locationKeychain)
+	}
+
+	if !errors.Is(err, errKeychainUnavailable) {
+		return err
+	}
+
+	if err := s.secondary.Save(credentials); err != nil {
+		return err
+	}
+	return writeLoginMarker(s.dir, locationFile)
+}
+
+func (s *fallbackStore) Delete() error {
+	err := s.primary.Delete()
+	if err != nil && !errors.Is(err, errKeychainUnavailable) {
+		return err
+	}
+
+	if err := s.secondary.Delete(); err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(s.dir, loginMarkerFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func readLoginMarker(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, loginMarkerFileName))
+	if os.IsNotExist(err) {
+		return "", ErrNotLoggedIn
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func writeLoginMarker(dir string, location string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(dir, loginMarkerFileName), []byte(location))
+}
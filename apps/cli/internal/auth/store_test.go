@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/oauth2"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *StoreTestSuite) Test_file_store_round_trips_encrypted_credentials() {
+	store := NewEncryptedFileStore(s.dir)
+	credentials := testCredentials()
+
+	s.Require().NoError(store.Save(credentials))
+
+	data, err := os.ReadFile(filepath.Join(s.dir, credentialsFileName))
+	s.Require().NoError(err)
+	s.Assert().NotContains(string(data), "access-token-1")
+	s.Assert().NotContains(string(data), "client-secret")
+
+	info, err := os.Stat(filepath.Join(s.dir, keyFileName))
+	s.Require().NoError(err)
+	s.Assert().Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	loaded, err := store.Load()
+	s.Require().NoError(err)
+	s.Assert().Equal(credentials.ClientID, loaded.ClientID)
+	s.Assert().Equal(credentials.ClientSecret, loaded.ClientSecret)
+	s.Assert().Equal(credentials.Token.AccessToken, loaded.Token.AccessToken)
+}
+
+func (s *StoreTestSuite) Test_file_store_load_without_credentials() {
+	_, err := NewEncryptedFileStore(s.dir).Load()
+	s.Assert().ErrorIs(err, ErrNotLoggedIn)
+}
+
+func (s *StoreTestSuite) Test_file_store_delete_removes_credentials() {
+	store := NewEncryptedFileStore(s.dir)
+	s.Require().NoError(store.Save(testCredentials()))
+
+	s.Require().NoError(store.Delete())
+	s.Require().NoError(store.Delete())
+
+	_, err := store.Load()
+	s.Assert().ErrorIs(err, ErrNotLoggedIn)
+}
+
+func (s *StoreTestSuite) Test_file_store_fails_to_load_with_wrong_key() {
+	store := NewEncryptedFileStore(s.dir)
+	s.Require().NoError(store.Save(testCredentials()))
+	s.Require().NoError(os.WriteFile(
+		filepath.Join(s.dir, keyFileName),
+		[]byte(strings.Repeat("k", keySize)),
+		0o600,
+	))
+
+	_, err := store.Load()
+	s.Assert().ErrorContains(err, "failed to decrypt credentials")
+}
+
+func (s *StoreTestSuite) Test_falls_back_to_file_when_keychain_is_unavailable() {
+	store := &fallbackStore{
+		primary:   &keychainStore{run: failingRunner, goos: "linux"},
+		secondary: NewEncryptedFileStore(s.dir),
+		dir:       s.dir,
+	}
+
+	s.Require().NoError(store.Save(testCredentials()))
+	s.Assert().True(StoredInFile(s.dir))
+
+	loaded, err := store.Load()
+	s.Require().NoError(err)
+	s.Assert().Equal("celerity-cli", loaded.ClientID)
+
+	s.Require().NoError(store.Delete())
+	_, err = store.Load()
+	s.Assert().ErrorIs(err, ErrNotLoggedIn)
+}
+
+func (s *StoreTestSuite) Test_uses_keychain_when_available() {
+	keychain := newFakeKeychain()
+	store := &fallbackStore{
+		primary:   &keychainStore{run: keychain.run, goos: "linux"},
+		secondary: NewEncryptedFileStore(s.dir),
+		dir:       s.dir,
+	}
+
+	s.Require().NoError(store.Save(testCredentials()))
+	s.Assert().NotEmpty(keychain.secret)
+	s.Assert().NoFileExists(filepath.Join(s.dir, credentialsFileName))
+	s.Assert().False(StoredInFile(s.dir))
+
+	loaded, err := store.Load()
+	s.Require().NoError(err)
+	s.Assert().Equal("access-token-1", loaded.Token.AccessToken)
+
+	s.Require().NoError(store.Delete())
+	s.Assert().Empty(keychain.secret)
+	s.Assert().NoFileExists(filepath.Join(s.dir, loginMarkerFileName))
+}
+
+func (s *StoreTestSuite) Test_does_not_probe_keychain_without_login_marker() {
+	keychain := newFakeKeychain()
+	keychain.secret = "stale"
+	store := &fallbackStore{
+		primary:   &keychainStore{run: keychain.run, goos: "linux"},
+		secondary: NewEncryptedFileStore(s.dir),
+		dir:       s.dir,
+	}
+
+	_, err := store.Load()
+	s.Assert().ErrorIs(err, ErrNotLoggedIn)
+	s.Assert().Zero(keychain.calls)
+}
+
+func (s *StoreTestSuite) Test_keychain_is_unavailable_on_unsupported_platforms() {
+	store := &keychainStore{run: failingRunner, goos: "windows"}
+
+	_, err := store.Load()
+	s.Assert().ErrorIs(err, errKeychainUnavailable)
+	s.Assert().ErrorIs(store.Save(testCredentials()), errKeychainUnavailable)
+}
+
+func testCredentials() *Credentials {
+	return &Credentials{
+		Method:        MethodClientCredentials,
+		TokenEndpoint: "https://auth.example.com/token",
+		ClientID:      "celerity-cli",
+		ClientSecret:  "client-secret",
+		Token: &oauth2.Token{
+			AccessToken: "access-token-1",
+			TokenType:   "Bearer",
+		},
+	}
+}
+
+func failingRunner(_ string, name string, _ ...string) ([]byte, error) {
+	return nil, errors.New(name + ": executable file not found in $PATH")
+}
+
+// fakeKeychain emulates secret-tool with a single stored secret.
+type fakeKeychain struct {
+	secret string
+	calls  int
+}
+
+func newFakeKeychain() *fakeKeychain {
+	return &fakeKeychain{}
+}
+
+func (k *fakeKeychain) run(stdin string, _ string, args ...string) ([]byte, error) {
+	k.calls += 1
+	switch args[0] {
+	case "store":
+		k.secret = stdin
+		return nil, nil
+	case "lookup":
+		if k.secret == "" {
+			return nil, errors.New("not found")
+		}
+		return []byte(k.secret), nil
+	case "clear":
+		k.secret = ""
+		return nil, nil
+	}
+
+	return nil, errors.New("unexpected command")
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// NewTokenSource creates a token source for the stored credentials
+// that refreshes the access token when it expires, using the refresh token
+// for device code logins and the client credentials for machine logins.
+// Refreshed tokens are saved back to the store so they can be reused
+// by later commands.
+func NewTokenSource(
+	ctx context.Context,
+	store Store,
+	credentials *Credentials,
+	client *http.Client,
+) oauth2.TokenSource {
+	ctx = withHTTPClient(ctx, client)
+
+	var base oauth2.TokenSource
+	if credentials.Method == MethodClientCredentials {
+		base = clientCredentialsConfig(credentials).TokenSource(ctx)
+		if credentials.Token != nil {
+			base = oauth2.ReuseTokenSource(credentials.Token, base)
+		}
+	} else {
+		base = credentials.oauth2Config().TokenSource(ctx, credentials.Token)
+	}
+
+	return &persistingTokenSource{
+		base:        base,
+		store:       store,
+		credentials: credentials,
+	}
+}
+
+type persistingTokenSource struct {
+	mu          sync.Mutex
+	base        oauth2.TokenSource
+	store       Store
+	credentials *Credentials
+}
+
+func (s *persistingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.credentials.Token == nil || s.credentials.Token.AccessToken != token.AccessToken {
+		s.credentials.Token = token
+		if err := s.store.Save(s.credentials); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
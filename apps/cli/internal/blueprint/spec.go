@@ -0,0 +1,30 @@
+package blueprint
+
+import "github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+
+// Spec wraps a loaded blueprint to provide the blueprint spec
+// interface used by the blueprint framework to resolve substitutions
+// and group resources for links, without loading a blueprint container.
+type Spec struct {
+	blueprint *schema.Blueprint
+}
+
+// NewSpec creates a blueprint spec for a loaded blueprint.
+func NewSpec(blueprint *schema.Blueprint) *Spec {
+	return &Spec{blueprint: blueprint}
+}
+
+// ResourceSchema returns the schema for the resource with the given name,
+// nil is returned when the resource does not exist.
+func (s *Spec) ResourceSchema(resourceName string) *schema.Resource {
+	if s.blueprint.Resources == nil {
+		return nil
+	}
+
+	return s.blueprint.Resources.Values[resourceName]
+}
+
+// Schema returns the loaded blueprint.
+func (s *Spec) Schema() *schema.Blueprint {
+	return s.blueprint
+}
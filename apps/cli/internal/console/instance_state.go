@@ -0,0 +1,350 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/resourcehelpers"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/substitutions"
+)
+
+// instanceDataSourceTypePrefix is the prefix of the types that data sources
+// are given in the blueprint the console resolves expressions against.
+// The data source registry is only given the type of a data source when
+// fetching data, so each data source is given a type derived from its name
+// to look up the data recorded for it in the loaded instance.
+const instanceDataSourceTypePrefix = "console/datasource/"
+
+// withInstanceDataSourceTypes creates a shallow copy of the blueprint
+// where each data source has a type derived from its name.
+func withInstanceDataSourceTypes(blueprint *schema.Blueprint) *schema.Blueprint {
+	if blueprint.DataSources == nil {
+		return blueprint
+	}
+
+	copied := *blueprint
+	copied.DataSources = &schema.DataSourceMap{
+		Values:     make(map[string]*schema.DataSource, len(blueprint.DataSources.Values)),
+		SourceMeta: blueprint.DataSources.SourceMeta,
+	}
+	for name, dataSource := range blueprint.DataSources.Values {
+		if dataSource == nil {
+			continue
+		}
+		dataSourceCopy := *dataSource
+		dataSourceCopy.Type = &schema.DataSourceTypeWrapper{
+			Value: instanceDataSourceTypePrefix + name,
+		}
+		copied.DataSources.Values[name] = &dataSourceCopy
+	}
+
+	return &copied
+}
+
+// instanceDataSourceRegistry serves data source data from the state of the
+// loaded blueprint instance instead of fetching it with provider plugins.
+// The instance state does not hold the data fetched for data sources,
+// only the data source fields referenced by the exports of the blueprint
+// are recorded in the export state of the instance.
+type instanceDataSourceRegistry struct {
+	provider.DataSourceRegistry
+	instance *state.InstanceState
+	data     map[string]map[string]*bpcore.MappingNode
+}
+
+func newInstanceDataSourceRegistry(
+	registry provider.DataSourceRegistry,
+	instance *state.InstanceState,
+) *instanceDataSourceRegistry {
+	return &instanceDataSourceRegistry{
+		DataSourceRegistry: registry,
+		instance:           instance,
+		data:               dataSourceDataFromExports(instance),
+	}
+}
+
+func (r *instanceDataSourceRegistry) Fetch(
+	ctx context.Context,
+	dataSourceType string,
+	input *provider.DataSourceFetchInput,
+) (*provider.DataSourceFetchOutput, error) {
+	data, err := r.dataFor(dataSourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.DataSourceFetchOutput{Data: data}, nil
+}
+
+func (r *instanceDataSourceRegistry) GetSpecDefinition(
+	ctx context.Context,
+	dataSourceType string,
+	input *provider.DataSourceGetSpecDefinitionInput,
+) (*provider.DataSourceGetSpecDefinitionOutput, error) {
+	data, err := r.dataFor(dataSourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]*provider.DataSourceSpecSchema, len(data))
+	for name, value := range data {
+		fields[name] = &provider.DataSourceSpecSchema{
+			Type: provider.DataSourceSpecSchemaType(specSchemaFromValue(value).Type),
+		}
+	}
+
+	return &provider.DataSourceGetSpecDefinitionOutput{
+		SpecDefinition: &provider.DataSourceSpecDefinition{Fields: fields},
+	}, nil
+}
+
+// DataSourceValues returns the data recorded for a data source
+// in the state of the loaded instance.
+func (r *instanceDataSourceRegistry) DataSourceValues(name string) map[string]*bpcore.MappingNode {
+	return r.data[name]
+}
+
+func (r *instanceDataSourceRegistry) dataFor(
+	dataSourceType string,
+) (map[string]*bpcore.MappingNode, error) {
+	if r.instance == nil {
+		return nil, errNoInstanceLoaded
+	}
+
+	name := strings.TrimPrefix(dataSourceType, instanceDataSourceTypePrefix)
+	data, hasData := r.data[name]
+	if !hasData {
+		return nil, fmt.Errorf(
+			"no data for data source %q is recorded in instance %q, only data source fields "+
+				"referenced by blueprint exports are recorded in the instance state",
+			name,
+			r.instance.InstanceName,
+		)
+	}
+
+	return data, nil
+}
+
+// dataSourceDataFromExports collects the values of the exports sourced from
+// data source fields, keyed by data source name and field name.
+func dataSourceDataFromExports(instance *state.InstanceState) map[string]map[string]*bpcore.MappingNode {
+	data := map[string]map[string]*bpcore.MappingNode{}
+	if instance == nil {
+		return data
+	}
+
+	for _, export := range instance.Exports {
+		if export == nil || !strings.HasPrefix(export.Field, "datasources.") {
+			continue
+		}
+
+		parsed, err := substitutions.ParseSubstitutionValues(
+			"",
+			fmt.Sprintf("${%s}", export.Field),
+			nil,
+			false,
+			true,
+			0,
+		)
+		if err != nil || len(parsed) != 1 || parsed[0].SubstitutionValue == nil {
+			continue
+		}
+
+		property := parsed[0].SubstitutionValue.DataSourceProperty
+		// Exports of a single item of an array field do not hold
+		// the value of the whole field.
+		if property == nil || property.PrimitiveArrIndex != nil {
+			continue
+		}
+
+		if data[property.DataSourceName] == nil {
+			data[property.DataSourceName] = map[string]*bpcore.MappingNode{}
+		}
+		data[property.DataSourceName][property.FieldName] = export.Value
+	}
+
+	return data
+}
+
+// instanceResourceRegistry serves resource spec definitions inferred from
+// the spec data recorded for the resources in the loaded blueprint instance,
+// so references to resources can be resolved without provider plugins.
+type instanceResourceRegistry struct {
+	resourcehelpers.Registry
+	instance    *state.InstanceState
+	definitions map[string]*provider.ResourceDefinitionsSchema
+}
+
+func newInstanceResourceRegistry(
+	registry resourcehelpers.Registry,
+	instance *state.InstanceState,
+) *instanceResourceRegistry {
+	definitions := map[string]*provider.ResourceDefinitionsSchema{}
+	if instance != nil {
+		for _, resource := range instance.Resources {
+			if resource == nil {
+				continue
+			}
+			definitions[resource.Type] = mergeSpecSchemas(
+				definitions[resource.Type],
+				specSchemaFromValue(resource.SpecData),
+			)
+		}
+	}
+
+	return &instanceResourceRegistry{
+		Registry:    registry,
+		instance:    instance,
+		definitions: definitions,
+	}
+}
+
+func (r *instanceResourceRegistry) GetSpecDefinition(
+	ctx context.Context,
+	resourceType string,
+	input *provider.ResourceGetSpecDefinitionInput,
+) (*provider.ResourceGetSpecDefinitionOutput, error) {
+	if r.instance == nil {
+		return nil, errNoInstanceLoaded
+	}
+
+	definition, hasDefinition := r.definitions[resourceType]
+	if !hasDefinition {
+		return nil, fmt.Errorf(
+			"no resources of type %q are recorded in instance %q",
+			resourceType,
+			r.instance.InstanceName,
+		)
+	}
+
+	return &provider.ResourceGetSpecDefinitionOutput{
+		SpecDefinition: &provider.ResourceSpecDefinition{Schema: definition},
+	}, nil
+}
+
+// resourceCacheFromInstance creates a cache of the resources in the loaded
+// instance keyed by resource name, holding the deployed spec and metadata
+// that references to resources are resolved from.
+func resourceCacheFromInstance(instance *state.InstanceState) *bpcore.Cache[*provider.ResolvedResource] {
+	cache := bpcore.NewCache[*provider.ResolvedResource]()
+	if instance == nil {
+		return cache
+	}
+
+	for name, resourceID := range instance.ResourceIDs {
+		resource, hasResource := instance.Resources[resourceID]
+		if !hasResource || resource == nil {
+			continue
+		}
+
+		cache.Set(name, &provider.ResolvedResource{
+			Type:     &schema.ResourceTypeWrapper{Value: resource.Type},
+			Metadata: resolvedMetadataFromState(resource.Metadata),
+			Spec:     resource.SpecData,
+		})
+	}
+
+	return cache
+}
+
+func resolvedMetadataFromState(metadata *state.ResourceMetadataState) *provider.ResolvedResourceMetadata {
+	if metadata == nil {
+		return nil
+	}
+
+	resolved := &provider.ResolvedResourceMetadata{
+		Custom: metadata.Custom,
+	}
+	if metadata.DisplayName != "" {
+		resolved.DisplayName = bpcore.MappingNodeFromString(metadata.DisplayName)
+	}
+	if metadata.Annotations != nil {
+		resolved.Annotations = &bpcore.MappingNode{Fields: maps.Clone(metadata.Annotations)}
+	}
+	if metadata.Labels != nil {
+		resolved.Labels = &schema.StringMap{Values: maps.Clone(metadata.Labels)}
+	}
+
+	return resolved
+}
+
+// specSchemaFromValue infers the schema of a value recorded in the instance
+// state, values that are null are treated as strings.
+func specSchemaFromValue(value *bpcore.MappingNode) *provider.ResourceDefinitionsSchema {
+	switch {
+	case value == nil:
+		return &provider.ResourceDefinitionsSchema{Type: provider.ResourceDefinitionsSchemaTypeString}
+	case value.Fields != nil:
+		attributes := make(map[string]*provider.ResourceDefinitionsSchema, len(value.Fields))
+		for name, fieldValue := range value.Fields {
+			attributes[name] = specSchemaFromValue(fieldValue)
+		}
+		return &provider.ResourceDefinitionsSchema{
+			Type:       provider.ResourceDefinitionsSchemaTypeObject,
+			Attributes: attributes,
+		}
+	case value.Items != nil:
+		var items *provider.ResourceDefinitionsSchema
+		for _, item := range value.Items {
+			items = mergeSpecSchemas(items, specSchemaFromValue(item))
+		}
+		return &provider.ResourceDefinitionsSchema{
+			Type:  provider.ResourceDefinitionsSchemaTypeArray,
+			Items: items,
+		}
+	case value.Scalar != nil && value.Scalar.IntValue != nil:
+		return &provider.ResourceDefinitionsSchema{Type: provider.ResourceDefinitionsSchemaTypeInteger}
+	case value.Scalar != nil && value.Scalar.FloatValue != nil:
+		return &provider.ResourceDefinitionsSchema{Type: provider.ResourceDefinitionsSchemaTypeFloat}
+	case value.Scalar != nil && value.Scalar.BoolValue != nil:
+		return &provider.ResourceDefinitionsSchema{Type: provider.ResourceDefinitionsSchemaTypeBoolean}
+	}
+
+	return &provider.ResourceDefinitionsSchema{Type: provider.ResourceDefinitionsSchemaTypeString}
+}
+
+// mergeSpecSchemas combines the schemas inferred from the values of
+// resources of the same type, so a path that exists in the spec of any
+// of the resources can be resolved.
+func mergeSpecSchemas(a, b *provider.ResourceDefinitionsSchema) *provider.ResourceDefinitionsSchema {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	switch {
+	case a.Type == provider.ResourceDefinitionsSchemaTypeObject &&
+		b.Type == provider.ResourceDefinitionsSchemaTypeObject:
+		attributes := maps.Clone(a.Attributes)
+		for name, attribute := range b.Attributes {
+			attributes[name] = mergeSpecSchemas(attributes[name], attribute)
+		}
+		return &provider.ResourceDefinitionsSchema{
+			Type:       provider.ResourceDefinitionsSchemaTypeObject,
+			Attributes: attributes,
+		}
+	case a.Type == provider.ResourceDefinitionsSchemaTypeArray &&
+		b.Type == provider.ResourceDefinitionsSchemaTypeArray:
+		return &provider.ResourceDefinitionsSchema{
+			Type:  provider.ResourceDefinitionsSchemaTypeArray,
+			Items: mergeSpecSchemas(a.Items, b.Items),
+		}
+	case isScalarSpecSchema(a) && !isScalarSpecSchema(b):
+		return b
+	}
+
+	return a
+}
+
+func isScalarSpecSchema(schema *provider.ResourceDefinitionsSchema) bool {
+	return schema.Type != provider.ResourceDefinitionsSchemaTypeObject &&
+		schema.Type != provider.ResourceDefinitionsSchemaTypeArray
+}
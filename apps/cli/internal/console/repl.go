@@ -0,0 +1,249 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+)
+
+const prompt = "> "
+
+const helpText = `Enter an expression to evaluate it against the loaded blueprint, for example:
+
+  variables.region
+  values.tableName
+  trim(to_upper(variables.environment))
+  ${variables.appName}-${variables.environment}
+
+Commands:
+  :help               Show this help message
+  :vars               List blueprint variables and their values
+  :values             Resolve and list blueprint values
+  :datasources        List data sources, their exported fields and the values
+                      recorded in the loaded instance
+  :functions          List the functions available in expressions
+  :resources          List resources in the loaded blueprint instance
+  :resource <name>    Show the state of a resource in the loaded instance
+  :exports            Show the exports of the loaded blueprint instance
+  :quit               Exit the console`
+
+// REPL provides a read-eval-print loop for evaluating
+// substitution expressions in a console session.
+type REPL struct {
+	session *Session
+	in      io.Reader
+	out     io.Writer
+}
+
+// NewREPL creates a new read-eval-print loop that reads expressions
+// and commands from the given reader and writes results to the given writer.
+func NewREPL(session *Session, in io.Reader, out io.Writer) *REPL {
+	return &REPL{
+		session: session,
+		in:      in,
+		out:     out,
+	}
+}
+
+// Run starts the loop, it returns when the input is exhausted,
+// the user exits the console or the context is cancelled.
+func (r *REPL) Run(ctx context.Context) error {
+	fmt.Fprintln(r.out, "Celerity console, type :help for usage or :quit to exit.")
+
+	scanner := bufio.NewScanner(r.in)
+	for {
+		fmt.Fprint(r.out, prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if line == ":quit" || line == ":exit" {
+			return nil
+		}
+
+		r.handleLine(ctx, line)
+	}
+}
+
+func (r *REPL) handleLine(ctx context.Context, line string) {
+	if !strings.HasPrefix(line, ":") {
+		r.evaluate(ctx, line)
+		return
+	}
+
+	command, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch command {
+	case ":help":
+		fmt.Fprintln(r.out, helpText)
+	case ":vars":
+		r.printNamedValues(r.session.Variables(), "variables")
+	case ":values":
+		r.printNamedValues(r.session.Values(ctx), "values")
+	case ":datasources":
+		r.printDataSources()
+	case ":functions":
+		fmt.Fprintln(r.out, strings.Join(r.session.Functions(), ", "))
+	case ":resources":
+		r.printResources()
+	case ":resource":
+		r.printResource(arg)
+	case ":exports":
+		r.printExports()
+	default:
+		fmt.Fprintf(r.out, "unknown command %q, type :help for usage\n", command)
+	}
+}
+
+func (r *REPL) evaluate(ctx context.Context, expression string) {
+	value, err := r.session.Eval(ctx, expression)
+	if err != nil {
+		fmt.Fprintf(r.out, "error: %s\n", err)
+		return
+	}
+
+	if r.session.IsSecret(expression) {
+		fmt.Fprintln(r.out, "<secret>")
+		return
+	}
+
+	fmt.Fprintln(r.out, FormatValue(value))
+}
+
+func (r *REPL) printNamedValues(values []NamedValue, kind string) {
+	if len(values) == 0 {
+		fmt.Fprintf(r.out, "no %s defined in the blueprint\n", kind)
+		return
+	}
+
+	for _, value := range values {
+		fmt.Fprintf(r.out, "%s (%s) = %s\n", value.Name, value.Type, renderNamedValue(value))
+	}
+}
+
+func renderNamedValue(value NamedValue) string {
+	if value.Err != nil {
+		return fmt.Sprintf("<error: %s>", value.Err)
+	}
+
+	if value.Secret {
+		return "<secret>"
+	}
+
+	if value.Value == nil {
+		return "<not set>"
+	}
+
+	return FormatValue(value.Value)
+}
+
+func (r *REPL) printDataSources() {
+	dataSources := r.session.DataSources()
+	if len(dataSources) == 0 {
+		fmt.Fprintln(r.out, "no data sources defined in the blueprint")
+		return
+	}
+
+	for _, dataSource := range dataSources {
+		fmt.Fprintf(
+			r.out,
+			"%s (%s) exports: %s\n",
+			dataSource.Name,
+			dataSource.Type,
+			strings.Join(dataSource.Exports, ", "),
+		)
+
+		fields := slices.Sorted(maps.Keys(dataSource.Values))
+		for _, field := range fields {
+			fmt.Fprintf(r.out, "  %s = %s\n", field, FormatValue(dataSource.Values[field]))
+		}
+	}
+
+	if r.session.Instance() == nil {
+		fmt.Fprintln(
+			r.out,
+			"data source values are fetched by provider plugins during deployment, "+
+				"use the --instance flag to load the values recorded for a deployed instance",
+		)
+	}
+}
+
+func (r *REPL) printResources() {
+	instance := r.session.Instance()
+	if instance == nil {
+		fmt.Fprintf(r.out, "error: %s\n", errNoInstanceLoaded)
+		return
+	}
+
+	names := make([]string, 0, len(instance.ResourceIDs))
+	for name := range instance.ResourceIDs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		resource, err := r.session.Resource(name)
+		if err != nil {
+			fmt.Fprintf(r.out, "%s <error: %s>\n", name, err)
+			continue
+		}
+		fmt.Fprintf(r.out, "%s (%s) %s\n", name, resource.Type, engine.ResourceStatusLabel(resource.Status))
+	}
+}
+
+func (r *REPL) printResource(name string) {
+	if name == "" {
+		fmt.Fprintln(r.out, "usage: :resource <name>")
+		return
+	}
+
+	resource, err := r.session.Resource(name)
+	if err != nil {
+		fmt.Fprintf(r.out, "error: %s\n", err)
+		return
+	}
+
+	fmt.Fprintf(
+		r.out,
+		"id: %s\ntype: %s\nstatus: %s\n",
+		resource.ResourceID,
+		resource.Type,
+		engine.ResourceStatusLabel(resource.Status),
+	)
+	fmt.Fprintf(r.out, "spec: %s\n", FormatValue(resource.SpecData))
+}
+
+func (r *REPL) printExports() {
+	instance := r.session.Instance()
+	if instance == nil {
+		fmt.Fprintf(r.out, "error: %s\n", errNoInstanceLoaded)
+		return
+	}
+
+	names := make([]string, 0, len(instance.Exports))
+	for name := range instance.Exports {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		export := instance.Exports[name]
+		fmt.Fprintf(r.out, "%s = %s\n", name, FormatValue(export.Value))
+	}
+}
@@ -0,0 +1,106 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deployconfig"
+	"github.com/stretchr/testify/suite"
+)
+
+type REPLTestSuite struct {
+	suite.Suite
+	session *Session
+}
+
+func TestREPLTestSuite(t *testing.T) {
+	suite.Run(t, new(REPLTestSuite))
+}
+
+func (s *REPLTestSuite) SetupTest() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+	s.session = NewSession(bp, deployconfig.Empty(), nil)
+}
+
+func (s *REPLTestSuite) run(input string) string {
+	var out bytes.Buffer
+	repl := NewREPL(s.session, strings.NewReader(input), &out)
+	s.Require().NoError(repl.Run(context.Background()))
+	return out.String()
+}
+
+func (s *REPLTestSuite) Test_evaluates_expressions_line_by_line() {
+	out := s.run("variables.environment\nvalues.tableName\n")
+	s.Assert().Contains(out, `"dev"`)
+	s.Assert().Contains(out, `"orders-dev"`)
+}
+
+func (s *REPLTestSuite) Test_prints_errors_and_continues() {
+	out := s.run("variables.missing\nvariables.environment\n")
+	s.Assert().Contains(out, "error:")
+	s.Assert().Contains(out, `"dev"`)
+}
+
+func (s *REPLTestSuite) Test_quit_stops_processing_input() {
+	out := s.run(":quit\nvariables.environment\n")
+	s.Assert().NotContains(out, `"dev"`)
+}
+
+func (s *REPLTestSuite) Test_vars_command_masks_secrets() {
+	out := s.run(":vars\n")
+	s.Assert().Contains(out, "apiKey (string) = <secret>")
+	s.Assert().Contains(out, `environment (string) = "dev"`)
+	s.Assert().Contains(out, "region (string) = <not set>")
+}
+
+func (s *REPLTestSuite) Test_datasources_command_lists_exports() {
+	out := s.run(":datasources\n")
+	s.Assert().Contains(out, "network (aws/vpc) exports: subnetIds, vpcId")
+}
+
+func (s *REPLTestSuite) Test_masks_expressions_derived_from_secrets() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+	config := deployconfig.Empty()
+	config.BlueprintVariables["apiKey"] = bpcore.ScalarFromString("key-123")
+	s.session = NewSession(bp, config, nil)
+
+	out := s.run("variables.apiKey\nto_upper(variables.apiKey)\n${variables.environment}-${variables.apiKey}\n")
+	s.Assert().NotContains(strings.ToLower(out), "key-123")
+	s.Assert().Equal(3, strings.Count(out, "<secret>"))
+}
+
+func (s *REPLTestSuite) Test_datasources_command_shows_values_from_instance() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+	s.session = NewSession(bp, deployconfig.Empty(), &state.InstanceState{
+		InstanceID:   "instance-1",
+		InstanceName: "orders",
+		Exports: map[string]*state.ExportState{
+			"vpc": {
+				Field: "datasources.network.vpcId",
+				Value: bpcore.MappingNodeFromString("vpc-123"),
+			},
+		},
+	})
+
+	out := s.run(":datasources\n")
+	s.Assert().Contains(out, "network (aws/vpc) exports: subnetIds, vpcId\n  vpcId = \"vpc-123\"")
+	s.Assert().NotContains(out, "use the --instance flag")
+}
+
+func (s *REPLTestSuite) Test_resources_command_without_instance_reports_error() {
+	out := s.run(":resources\n")
+	s.Assert().Contains(out, "no blueprint instance loaded")
+}
+
+func (s *REPLTestSuite) Test_unknown_command() {
+	out := s.run(":unknown\n")
+	s.Assert().Contains(out, `unknown command ":unknown"`)
+}
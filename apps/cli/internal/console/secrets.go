@@ -0,0 +1,108 @@
+package console
+
+import (
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/substitutions"
+)
+
+// secretTracker determines whether expressions reveal the value of secret
+// blueprint variables or values, either by referencing them directly or
+// through functions and values that are derived from them.
+type secretTracker struct {
+	blueprint *schema.Blueprint
+	// visiting guards against cycles between values that reference each other,
+	// which are reported as errors when the values are resolved.
+	visiting map[string]bool
+}
+
+func newSecretTracker(blueprint *schema.Blueprint) *secretTracker {
+	return &secretTracker{
+		blueprint: blueprint,
+		visiting:  map[string]bool{},
+	}
+}
+
+func (t *secretTracker) inParts(parts []*substitutions.StringOrSubstitution) bool {
+	for _, part := range parts {
+		if part != nil && t.inSubstitution(part.SubstitutionValue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *secretTracker) inSubstitution(sub *substitutions.Substitution) bool {
+	if sub == nil {
+		return false
+	}
+
+	switch {
+	case sub.Variable != nil:
+		return t.isSecretVariable(sub.Variable.VariableName)
+	case sub.ValueReference != nil:
+		return t.isSecretValue(sub.ValueReference.ValueName)
+	case sub.Function != nil:
+		for _, arg := range sub.Function.Arguments {
+			if arg != nil && t.inSubstitution(arg.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *secretTracker) isSecretVariable(name string) bool {
+	if t.blueprint.Variables == nil {
+		return false
+	}
+
+	variable, hasVariable := t.blueprint.Variables.Values[name]
+	return hasVariable && variable != nil && bpcore.BoolValueFromScalar(variable.Secret)
+}
+
+func (t *secretTracker) isSecretValue(name string) bool {
+	if t.blueprint.Values == nil || t.visiting[name] {
+		return false
+	}
+
+	value, hasValue := t.blueprint.Values.Values[name]
+	if !hasValue || value == nil {
+		return false
+	}
+
+	if bpcore.BoolValueFromScalar(value.Secret) {
+		return true
+	}
+
+	t.visiting[name] = true
+	defer delete(t.visiting, name)
+	return t.inMappingNode(value.Value)
+}
+
+func (t *secretTracker) inMappingNode(node *bpcore.MappingNode) bool {
+	if node == nil {
+		return false
+	}
+
+	if node.StringWithSubstitutions != nil &&
+		t.inParts(node.StringWithSubstitutions.Values) {
+		return true
+	}
+
+	for _, field := range node.Fields {
+		if t.inMappingNode(field) {
+			return true
+		}
+	}
+
+	for _, item := range node.Items {
+		if t.inMappingNode(item) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,447 @@
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/providerhelpers"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/resourcehelpers"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/subengine"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/substitutions"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	cliblueprint "github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+)
+
+const (
+	// consoleElementName is the element name that expressions entered
+	// in the console are resolved in the context of.
+	consoleElementName = "console"
+	// consoleElementProperty is the property name that expressions entered
+	// in the console are resolved in the context of.
+	consoleElementProperty = "expression"
+)
+
+// Session holds a loaded blueprint along with the substitution resolver
+// that is used to evaluate expressions entered in the console.
+// A session can optionally hold the state of a deployed blueprint instance
+// that can be inspected and used by functions such as `link`.
+// Provider plugins are not loaded into the console, references to resources
+// and data sources are resolved from the state of the loaded instance.
+type Session struct {
+	blueprint   *schema.Blueprint
+	params      bpcore.BlueprintParams
+	instance    *state.InstanceState
+	resolver    subengine.SubstitutionResolver
+	dataSources *instanceDataSourceRegistry
+	functions   []string
+}
+
+// NewSession creates a new console session for the given blueprint.
+// Blueprint variables and plugin configuration are sourced from the provided
+// deploy configuration, variables without a value in the deploy configuration
+// fall back to the defaults defined in the blueprint.
+// The instance state is optional and can be nil, when it is set, the deployed
+// spec of resources and the data source fields recorded in the instance
+// exports are available in expressions.
+func NewSession(
+	blueprint *schema.Blueprint,
+	config *types.BlueprintOperationConfig,
+	instance *state.InstanceState,
+) *Session {
+	params := bpcore.NewDefaultParams(
+		config.Providers,
+		config.Transformers,
+		config.ContextVariables,
+		config.BlueprintVariables,
+	)
+
+	coreProvider := providerhelpers.NewCoreProvider(
+		&instanceLinkStateRetriever{instance: instance},
+		instanceIDRetriever(instance),
+		os.Getwd,
+		&bpcore.SystemClock{},
+	)
+	providers := map[string]provider.Provider{
+		"core": coreProvider,
+	}
+
+	dataSources := newInstanceDataSourceRegistry(
+		provider.NewDataSourceRegistry(
+			providers,
+			&bpcore.SystemClock{},
+			bpcore.NewNopLogger(),
+		),
+		instance,
+	)
+	resolver := subengine.NewDefaultSubstitutionResolver(
+		&subengine.Registries{
+			FuncRegistry: provider.NewFunctionRegistry(providers),
+			ResourceRegistry: newInstanceResourceRegistry(
+				resourcehelpers.NewRegistry(
+					providers,
+					nil,
+					time.Second,
+					params,
+				),
+				instance,
+			),
+			DataSourceRegistry: dataSources,
+		},
+		// The console does not mutate state, resource state is exposed
+		// through the loaded instance state instead.
+		nil,
+		resourceCacheFromInstance(instance),
+		bpcore.NewCache[[]*bpcore.MappingNode](),
+		bpcore.NewCache[*subengine.ChildExportFieldInfo](),
+		cliblueprint.NewSpec(withInstanceDataSourceTypes(blueprint)),
+		params,
+	)
+
+	functions, _ := coreProvider.ListFunctions(context.Background())
+	functions = slices.DeleteFunc(functions, isInternalFunction)
+	slices.Sort(functions)
+
+	return &Session{
+		blueprint:   blueprint,
+		params:      params,
+		instance:    instance,
+		resolver:    resolver,
+		dataSources: dataSources,
+		functions:   functions,
+	}
+}
+
+// Eval evaluates an expression against the loaded blueprint.
+// The expression can be the contents of a substitution (e.g. `variables.region`)
+// or a string that contains one or more `${..}` substitutions
+// (e.g. `${variables.region}-${values.suffix}`).
+// An expression made up of a single substitution resolves to the value
+// of the substitution, otherwise the resolved values are interpolated into a string.
+func (s *Session) Eval(ctx context.Context, expression string) (*bpcore.MappingNode, error) {
+	parsed, err := parseExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.instance != nil {
+		ctx = context.WithValue(ctx, bpcore.BlueprintInstanceIDKey, s.instance.InstanceID)
+	}
+
+	if len(parsed) == 1 {
+		return s.resolve(ctx, parsed[0])
+	}
+
+	var sb strings.Builder
+	for _, part := range parsed {
+		resolved, err := s.resolve(ctx, part)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bpcore.IsScalarMappingNode(resolved) {
+			return nil, fmt.Errorf(
+				"only scalar values can be interpolated into a string, " +
+					"wrap the substitution in a function such as jsondecode or use a single substitution",
+			)
+		}
+		sb.WriteString(resolved.Scalar.ToString())
+	}
+
+	return bpcore.MappingNodeFromString(sb.String()), nil
+}
+
+// IsSecret determines whether the result of an expression reveals the value
+// of a secret variable or value, either by referencing it directly or through
+// functions and values derived from it.
+// The results of these expressions should be masked when displayed.
+func (s *Session) IsSecret(expression string) bool {
+	parsed, err := parseExpression(expression)
+	if err != nil {
+		return false
+	}
+
+	return newSecretTracker(s.blueprint).inParts(parsed)
+}
+
+func parseExpression(expression string) ([]*substitutions.StringOrSubstitution, error) {
+	trimmed := strings.TrimSpace(expression)
+	if trimmed == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+
+	if !strings.Contains(trimmed, "${") {
+		trimmed = fmt.Sprintf("${%s}", trimmed)
+	}
+
+	return substitutions.ParseSubstitutionValues("", trimmed, nil, false, true, 0)
+}
+
+func (s *Session) resolve(
+	ctx context.Context,
+	value *substitutions.StringOrSubstitution,
+) (*bpcore.MappingNode, error) {
+	if value.StringValue != nil {
+		return bpcore.MappingNodeFromString(*value.StringValue), nil
+	}
+
+	result, err := s.resolver.ResolveSubstitution(
+		ctx,
+		value,
+		consoleElementName,
+		consoleElementProperty,
+		&subengine.ResolveTargetInfo{
+			ResolveFor: subengine.ResolveForChangeStaging,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.ResolveOnDeploy) > 0 {
+		return nil, fmt.Errorf(
+			"expression can only be resolved during deployment, "+
+				"depends on: %s",
+			strings.Join(result.ResolveOnDeploy, ", "),
+		)
+	}
+
+	return result.Resolved, nil
+}
+
+// Variables returns the names of the variables defined in the blueprint
+// along with their current values sorted by name.
+// Variables that do not have a value provided in the deploy configuration
+// or a default in the blueprint will have a nil value.
+func (s *Session) Variables() []NamedValue {
+	if s.blueprint.Variables == nil {
+		return nil
+	}
+
+	vars := make([]NamedValue, 0, len(s.blueprint.Variables.Values))
+	for name, variable := range s.blueprint.Variables.Values {
+		value := s.params.BlueprintVariable(name)
+		if value == nil {
+			value = variable.Default
+		}
+
+		varType := ""
+		if variable.Type != nil {
+			varType = string(variable.Type.Value)
+		}
+
+		vars = append(vars, NamedValue{
+			Name:   name,
+			Type:   varType,
+			Value:  scalarToMappingNode(value),
+			Secret: bpcore.BoolValueFromScalar(variable.Secret),
+		})
+	}
+
+	sortNamedValues(vars)
+	return vars
+}
+
+// Values resolves all the values defined in the blueprint
+// and returns them sorted by name.
+// Values that fail to resolve will have the error attached,
+// values derived from secret variables are marked as secret.
+func (s *Session) Values(ctx context.Context) []NamedValue {
+	if s.blueprint.Values == nil {
+		return nil
+	}
+
+	values := make([]NamedValue, 0, len(s.blueprint.Values.Values))
+	for name, value := range s.blueprint.Values.Values {
+		valueType := ""
+		if value.Type != nil {
+			valueType = string(value.Type.Value)
+		}
+
+		reference := fmt.Sprintf("values.%s", name)
+		resolved, err := s.Eval(ctx, reference)
+		values = append(values, NamedValue{
+			Name:   name,
+			Type:   valueType,
+			Value:  resolved,
+			Secret: s.IsSecret(reference),
+			Err:    err,
+		})
+	}
+
+	sortNamedValues(values)
+	return values
+}
+
+// DataSources returns a summary of the data sources defined in the blueprint
+// sorted by name.
+// When an instance is loaded, the summary includes the data source fields
+// recorded in the instance state.
+func (s *Session) DataSources() []DataSourceSummary {
+	if s.blueprint.DataSources == nil {
+		return nil
+	}
+
+	summaries := make([]DataSourceSummary, 0, len(s.blueprint.DataSources.Values))
+	for name, dataSource := range s.blueprint.DataSources.Values {
+		summary := DataSourceSummary{
+			Name:   name,
+			Values: s.dataSources.DataSourceValues(name),
+		}
+		if dataSource.Type != nil {
+			summary.Type = dataSource.Type.Value
+		}
+
+		if dataSource.Exports != nil {
+			for field := range dataSource.Exports.Values {
+				summary.Exports = append(summary.Exports, field)
+			}
+			slices.Sort(summary.Exports)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	slices.SortFunc(summaries, func(a, b DataSourceSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return summaries
+}
+
+// Functions returns the names of the functions that can be used
+// in expressions in the console sorted alphabetically.
+func (s *Session) Functions() []string {
+	return s.functions
+}
+
+// Instance returns the state of the blueprint instance loaded
+// into the session, this will be nil if no instance was loaded.
+func (s *Session) Instance() *state.InstanceState {
+	return s.instance
+}
+
+// Resource retrieves the state of a resource by its logical name
+// from the loaded blueprint instance.
+func (s *Session) Resource(name string) (*state.ResourceState, error) {
+	if s.instance == nil {
+		return nil, errNoInstanceLoaded
+	}
+
+	resourceID, hasID := s.instance.ResourceIDs[name]
+	if !hasID {
+		return nil, fmt.Errorf("resource %q not found in instance %q", name, s.instance.InstanceName)
+	}
+
+	resource, hasResource := s.instance.Resources[resourceID]
+	if !hasResource {
+		return nil, fmt.Errorf("resource %q not found in instance %q", name, s.instance.InstanceName)
+	}
+
+	return resource, nil
+}
+
+// NamedValue holds a named variable or value from a blueprint
+// along with the value it resolves to.
+type NamedValue struct {
+	Name   string
+	Type   string
+	Value  *bpcore.MappingNode
+	Secret bool
+	Err    error
+}
+
+// DataSourceSummary holds the information about a data source
+// defined in a blueprint that is displayed in the console.
+type DataSourceSummary struct {
+	Name    string
+	Type    string
+	Exports []string
+	// Values holds the data source fields recorded in the state
+	// of the loaded instance.
+	Values map[string]*bpcore.MappingNode
+}
+
+// FormatValue renders a resolved mapping node as indented JSON
+// for display in the console.
+func FormatValue(value *bpcore.MappingNode) string {
+	if value == nil {
+		return "null"
+	}
+
+	if bpcore.IsScalarMappingNode(value) && bpcore.IsScalarString(value.Scalar) {
+		return fmt.Sprintf("%q", value.Scalar.ToString())
+	}
+
+	data, err := json.MarshalIndent(subengine.MappingNodeToGoValue(value), "", "  ")
+	if err != nil {
+		return fmt.Sprintf("<unable to render value: %s>", err)
+	}
+
+	return string(data)
+}
+
+func scalarToMappingNode(value *bpcore.ScalarValue) *bpcore.MappingNode {
+	if value == nil {
+		return nil
+	}
+
+	return &bpcore.MappingNode{Scalar: value}
+}
+
+func sortNamedValues(values []NamedValue) {
+	slices.SortFunc(values, func(a, b NamedValue) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Internal functions are prefixed with an underscore and are used
+// by the blueprint framework to execute higher-order functions,
+// they are not intended to be called directly.
+func isInternalFunction(name string) bool {
+	return strings.HasPrefix(name, "_")
+}
+
+type instanceLinkStateRetriever struct {
+	instance *state.InstanceState
+}
+
+func (r *instanceLinkStateRetriever) GetByName(
+	ctx context.Context,
+	instanceID string,
+	linkName string,
+) (state.LinkState, error) {
+	if r.instance == nil {
+		return state.LinkState{}, errNoInstanceLoaded
+	}
+
+	for _, link := range r.instance.Links {
+		if link != nil && link.Name == linkName {
+			return *link, nil
+		}
+	}
+
+	return state.LinkState{}, state.LinkNotFoundError(linkName)
+}
+
+func instanceIDRetriever(instance *state.InstanceState) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		if instance == nil {
+			return "", errNoInstanceLoaded
+		}
+
+		return instance.InstanceID, nil
+	}
+}
+
+var errNoInstanceLoaded = errors.New(
+	"no blueprint instance loaded, use the --instance flag to load the state of a deployed instance",
+)
@@ -0,0 +1,288 @@
+package console
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deployconfig"
+	"github.com/stretchr/testify/suite"
+)
+
+const testBlueprint = `
+version: 2025-11-02
+variables:
+  environment:
+    type: string
+    default: dev
+  region:
+    type: string
+  apiKey:
+    type: string
+    secret: true
+values:
+  tableName:
+    type: string
+    value: "orders-${variables.environment}"
+  tags:
+    type: array
+    value: ["${variables.environment}", "orders"]
+datasources:
+  network:
+    type: aws/vpc
+    filter:
+      field: tags
+      operator: "has key"
+      search: "main"
+    exports:
+      vpcId:
+        type: string
+      subnetIds:
+        type: array
+resources:
+  ordersTable:
+    type: aws/dynamodb/table
+    spec:
+      tableName: "${values.tableName}"
+`
+
+type SessionTestSuite struct {
+	suite.Suite
+	session *Session
+}
+
+func TestSessionTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionTestSuite))
+}
+
+func (s *SessionTestSuite) SetupTest() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+
+	config := deployconfig.Empty()
+	config.BlueprintVariables["region"] = bpcore.ScalarFromString("eu-west-2")
+	s.session = NewSession(bp, config, nil)
+}
+
+func (s *SessionTestSuite) Test_evaluates_variable_reference() {
+	value, err := s.session.Eval(context.Background(), "variables.region")
+	s.Require().NoError(err)
+	s.Assert().Equal("eu-west-2", bpcore.StringValue(value))
+}
+
+func (s *SessionTestSuite) Test_evaluates_variable_default() {
+	value, err := s.session.Eval(context.Background(), "${variables.environment}")
+	s.Require().NoError(err)
+	s.Assert().Equal("dev", bpcore.StringValue(value))
+}
+
+func (s *SessionTestSuite) Test_evaluates_value_with_substitutions() {
+	value, err := s.session.Eval(context.Background(), "values.tableName")
+	s.Require().NoError(err)
+	s.Assert().Equal("orders-dev", bpcore.StringValue(value))
+}
+
+func (s *SessionTestSuite) Test_evaluates_function_call() {
+	value, err := s.session.Eval(context.Background(), `to_upper(trim("  orders  "))`)
+	s.Require().NoError(err)
+	s.Assert().Equal("ORDERS", bpcore.StringValue(value))
+}
+
+func (s *SessionTestSuite) Test_interpolates_multiple_substitutions() {
+	value, err := s.session.Eval(
+		context.Background(),
+		"${variables.environment}.${variables.region}",
+	)
+	s.Require().NoError(err)
+	s.Assert().Equal("dev.eu-west-2", bpcore.StringValue(value))
+}
+
+func (s *SessionTestSuite) Test_returns_error_for_missing_variable() {
+	_, err := s.session.Eval(context.Background(), "variables.missing")
+	s.Assert().Error(err)
+}
+
+func (s *SessionTestSuite) Test_returns_error_for_invalid_expression() {
+	_, err := s.session.Eval(context.Background(), "to_upper(")
+	s.Assert().Error(err)
+}
+
+func (s *SessionTestSuite) Test_returns_error_for_empty_expression() {
+	_, err := s.session.Eval(context.Background(), "   ")
+	s.Assert().ErrorContains(err, "expression is empty")
+}
+
+func (s *SessionTestSuite) Test_lists_variables_sorted_with_values() {
+	vars := s.session.Variables()
+	s.Require().Len(vars, 3)
+	s.Assert().Equal("apiKey", vars[0].Name)
+	s.Assert().True(vars[0].Secret)
+	s.Assert().Nil(vars[0].Value)
+	s.Assert().Equal("environment", vars[1].Name)
+	s.Assert().Equal("dev", bpcore.StringValue(vars[1].Value))
+	s.Assert().Equal("region", vars[2].Name)
+	s.Assert().Equal("eu-west-2", bpcore.StringValue(vars[2].Value))
+}
+
+func (s *SessionTestSuite) Test_resolves_values() {
+	values := s.session.Values(context.Background())
+	s.Require().Len(values, 2)
+	s.Assert().Equal("tableName", values[0].Name)
+	s.Assert().NoError(values[0].Err)
+	s.Assert().Equal("orders-dev", bpcore.StringValue(values[0].Value))
+	s.Assert().Equal("tags", values[1].Name)
+	s.Assert().NoError(values[1].Err)
+	s.Assert().Len(values[1].Value.Items, 2)
+}
+
+func (s *SessionTestSuite) Test_lists_data_sources() {
+	dataSources := s.session.DataSources()
+	s.Require().Len(dataSources, 1)
+	s.Assert().Equal("network", dataSources[0].Name)
+	s.Assert().Equal("aws/vpc", dataSources[0].Type)
+	s.Assert().Equal([]string{"subnetIds", "vpcId"}, dataSources[0].Exports)
+}
+
+func (s *SessionTestSuite) Test_lists_functions_without_internal_functions() {
+	functions := s.session.Functions()
+	s.Assert().Contains(functions, "to_upper")
+	s.Assert().Contains(functions, "jsondecode")
+	s.Assert().NotContains(functions, "_getattr_exec")
+}
+
+func (s *SessionTestSuite) Test_resource_lookup_requires_instance() {
+	_, err := s.session.Resource("ordersTable")
+	s.Assert().ErrorIs(err, errNoInstanceLoaded)
+}
+
+func (s *SessionTestSuite) Test_resource_lookup_from_instance_state() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+
+	session := NewSession(bp, deployconfig.Empty(), &state.InstanceState{
+		InstanceID:   "instance-1",
+		InstanceName: "orders",
+		ResourceIDs: map[string]string{
+			"ordersTable": "resource-1",
+		},
+		Resources: map[string]*state.ResourceState{
+			"resource-1": {
+				ResourceID: "resource-1",
+				Name:       "ordersTable",
+				Type:       "aws/dynamodb/table",
+				Status:     bpcore.ResourceStatusCreated,
+			},
+		},
+	})
+
+	resource, err := session.Resource("ordersTable")
+	s.Require().NoError(err)
+	s.Assert().Equal("resource-1", resource.ResourceID)
+
+	_, err = session.Resource("missing")
+	s.Assert().ErrorContains(err, `resource "missing" not found`)
+}
+
+func (s *SessionTestSuite) Test_evaluates_resources_and_data_sources_from_instance_state() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+
+	session := NewSession(bp, deployconfig.Empty(), &state.InstanceState{
+		InstanceID:   "instance-1",
+		InstanceName: "orders",
+		ResourceIDs: map[string]string{
+			"ordersTable": "resource-1",
+		},
+		Resources: map[string]*state.ResourceState{
+			"resource-1": {
+				ResourceID: "resource-1",
+				Name:       "ordersTable",
+				Type:       "aws/dynamodb/table",
+				SpecData: &bpcore.MappingNode{
+					Fields: map[string]*bpcore.MappingNode{
+						"tableName": bpcore.MappingNodeFromString("orders-prod"),
+						"arn":       bpcore.MappingNodeFromString("arn:aws:dynamodb:eu-west-2:123:table/orders-prod"),
+					},
+				},
+				Metadata: &state.ResourceMetadataState{DisplayName: "Orders"},
+			},
+		},
+		Exports: map[string]*state.ExportState{
+			"vpc": {
+				Field: "datasources.network.vpcId",
+				Value: bpcore.MappingNodeFromString("vpc-123"),
+			},
+			"subnets": {
+				Field: "datasources.network.subnetIds",
+				Value: bpcore.MappingNodeFromStringSlice([]string{"subnet-1", "subnet-2"}),
+			},
+		},
+	})
+
+	value, err := session.Eval(context.Background(), "datasources.network.vpcId")
+	s.Require().NoError(err)
+	s.Assert().Equal("vpc-123", bpcore.StringValue(value))
+
+	value, err = session.Eval(context.Background(), "datasources.network.subnetIds[1]")
+	s.Require().NoError(err)
+	s.Assert().Equal("subnet-2", bpcore.StringValue(value))
+
+	value, err = session.Eval(context.Background(), "to_upper(resources.ordersTable.spec.tableName)")
+	s.Require().NoError(err)
+	s.Assert().Equal("ORDERS-PROD", bpcore.StringValue(value))
+
+	value, err = session.Eval(context.Background(), "resources.ordersTable.spec.arn")
+	s.Require().NoError(err)
+	s.Assert().Equal("arn:aws:dynamodb:eu-west-2:123:table/orders-prod", bpcore.StringValue(value))
+
+	value, err = session.Eval(context.Background(), "resources.ordersTable.metadata.displayName")
+	s.Require().NoError(err)
+	s.Assert().Equal("Orders", bpcore.StringValue(value))
+
+	dataSources := session.DataSources()
+	s.Require().Len(dataSources, 1)
+	s.Assert().Equal("vpc-123", bpcore.StringValue(dataSources[0].Values["vpcId"]))
+}
+
+func (s *SessionTestSuite) Test_data_sources_require_instance() {
+	_, err := s.session.Eval(context.Background(), "datasources.network.vpcId")
+	s.Assert().ErrorContains(err, "no blueprint instance loaded")
+}
+
+func (s *SessionTestSuite) Test_detects_expressions_derived_from_secrets() {
+	derivedValues := `values:
+  apiKeyHeader:
+    type: string
+    value: "Bearer ${variables.apiKey}"
+  authHeaders:
+    type: object
+    value:
+      authorization: "${values.apiKeyHeader}"
+`
+	bp, err := schema.LoadString(
+		strings.Replace(testBlueprint, "values:\n", derivedValues, 1),
+		schema.YAMLSpecFormat,
+	)
+	s.Require().NoError(err)
+	session := NewSession(bp, deployconfig.Empty(), nil)
+
+	s.Assert().True(session.IsSecret("variables.apiKey"))
+	s.Assert().True(session.IsSecret("to_upper(trim(variables.apiKey))"))
+	s.Assert().True(session.IsSecret("${variables.region}-${variables.apiKey}"))
+	s.Assert().True(session.IsSecret("values.authHeaders"))
+	s.Assert().False(session.IsSecret("variables.region"))
+	s.Assert().False(session.IsSecret("values.tableName"))
+}
+
+func (s *SessionTestSuite) Test_formats_values() {
+	s.Assert().Equal(`"orders"`, FormatValue(bpcore.MappingNodeFromString("orders")))
+	s.Assert().Equal("10", FormatValue(bpcore.MappingNodeFromInt(10)))
+	s.Assert().Equal("null", FormatValue(nil))
+	s.Assert().Equal(
+		"[\n  \"a\",\n  \"b\"\n]",
+		FormatValue(bpcore.MappingNodeFromStringSlice([]string{"a", "b"})),
+	)
+}
@@ -0,0 +1,197 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// Options holds the options for staging changes and deploying
+// a blueprint instance.
+type Options struct {
+	// BlueprintFile is the path to the blueprint file on the local file system.
+	BlueprintFile string
+	// InstanceID is the ID of an existing blueprint instance to update,
+	// when empty, a new blueprint instance will be deployed.
+	InstanceID string
+	// Config is sent to the deploy engine to be used by plugins
+	// and as the source of blueprint variables.
+	Config *types.BlueprintOperationConfig
+}
+
+// StagedChanges holds the result of staging changes for a deployment.
+type StagedChanges struct {
+	ChangesetID string
+	Changes     *changes.BlueprintChanges
+}
+
+var errChangeStagingStreamClosed = errors.New(
+	"change staging stream closed before changes were staged",
+)
+
+// StageChanges creates a change set for the blueprint and waits for the change staging
+// process to complete.
+// The onEvent callback is called for each change staging event received
+// and can be nil.
+func StageChanges(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	onEvent func(*types.ChangeStagingEvent),
+	logger *zap.Logger,
+) (*StagedChanges, error) {
+	documentInfo, err := DocumentInfo(opts.BlueprintFile)
+	if err != nil {
+		return nil, err
+	}
+
+	changeset, err := deployEngine.CreateChangeset(
+		ctx,
+		&types.CreateChangesetPayload{
+			BlueprintDocumentInfo: documentInfo,
+			InstanceID:            opts.InstanceID,
+			Config:                opts.Config,
+		},
+	)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+
+	streamTo := make(chan types.ChangeStagingEvent)
+	errChan := make(chan error)
+	err = deployEngine.StreamChangeStagingEvents(ctx, changeset.ID, streamTo, errChan)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-errChan:
+			if err != nil {
+				return nil, err
+			}
+		case event, open := <-streamTo:
+			if !open {
+				return nil, errChangeStagingStreamClosed
+			}
+
+			if onEvent != nil {
+				onEvent(&event)
+			}
+
+			if complete, isComplete := event.AsCompleteChanges(); isComplete {
+				return &StagedChanges{
+					ChangesetID: changeset.ID,
+					Changes:     complete.Changes,
+				}, nil
+			}
+		}
+	}
+}
+
+// Start starts deploying the change set staged for the blueprint,
+// a new blueprint instance is created when an instance ID is not provided
+// in the options, otherwise the existing instance is updated.
+// This returns the ID of the blueprint instance that can be used
+// to stream deployment events.
+func Start(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	changesetID string,
+	logger *zap.Logger,
+) (string, error) {
+	documentInfo, err := DocumentInfo(opts.BlueprintFile)
+	if err != nil {
+		return "", err
+	}
+
+	payload := &types.BlueprintInstancePayload{
+		BlueprintDocumentInfo: documentInfo,
+		ChangeSetID:           changesetID,
+		Config:                opts.Config,
+	}
+
+	if opts.InstanceID == "" {
+		instance, err := deployEngine.CreateBlueprintInstance(ctx, payload)
+		if err != nil {
+			return "", engine.SimplifyError(err, logger)
+		}
+		return instance.InstanceID, nil
+	}
+
+	instance, err := deployEngine.UpdateBlueprintInstance(ctx, opts.InstanceID, payload)
+	if err != nil {
+		return "", engine.SimplifyError(err, logger)
+	}
+	return instance.InstanceID, nil
+}
+
+var errDeployStreamClosed = errors.New(
+	"deployment event stream closed before the deployment finished",
+)
+
+// Wait streams the deployment events for the given blueprint instance
+// until the deployment has finished, tracking the progress of each element.
+// The onEvent callback is called with the progress after each event
+// is applied and can be nil.
+func Wait(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	instanceID string,
+	onEvent func(*types.BlueprintInstanceEvent, *Progress),
+) (*Progress, error) {
+	streamTo := make(chan types.BlueprintInstanceEvent)
+	errChan := make(chan error)
+	err := deployEngine.StreamBlueprintInstanceEvents(ctx, instanceID, streamTo, errChan)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := NewProgress()
+	for {
+		select {
+		case <-ctx.Done():
+			return progress, ctx.Err()
+		case err := <-errChan:
+			if err != nil {
+				return progress, err
+			}
+		case event, open := <-streamTo:
+			if !open {
+				return progress, errDeployStreamClosed
+			}
+
+			finished := progress.Apply(&event)
+			if onEvent != nil {
+				onEvent(&event, progress)
+			}
+
+			if finished {
+				return progress, nil
+			}
+		}
+	}
+}
+
+// DocumentInfo produces the information about the location of
+// a blueprint file on the local file system that is sent to the deploy engine.
+func DocumentInfo(blueprintFile string) (types.BlueprintDocumentInfo, error) {
+	absPath, err := filepath.Abs(blueprintFile)
+	if err != nil {
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	return types.BlueprintDocumentInfo{
+		FileSourceScheme: "file",
+		Directory:        filepath.Dir(absPath),
+		BlueprintFile:    filepath.Base(absPath),
+	}, nil
+}
@@ -0,0 +1,215 @@
+package deploy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+)
+
+// ElementState holds the latest deployment status of an element
+// as reported in deployment events.
+type ElementState struct {
+	Kind           ElementKind
+	Name           string
+	Status         string
+	InProgress     bool
+	Failed         bool
+	RollingBack    bool
+	FailureReasons []string
+}
+
+// Progress tracks the status of the elements in a deployment
+// from the events streamed by the deploy engine.
+type Progress struct {
+	elements       map[string]*ElementState
+	instanceStatus bpcore.InstanceStatus
+	finished       bool
+	finish         *types.BlueprintInstanceEvent
+}
+
+// NewProgress creates a new tracker for the progress of a deployment.
+func NewProgress() *Progress {
+	return &Progress{
+		elements:       map[string]*ElementState{},
+		instanceStatus: bpcore.InstanceStatusPreparing,
+	}
+}
+
+// Apply updates the tracked progress with the given deployment event.
+// This returns true when the event marks the end of the deployment.
+func (p *Progress) Apply(event *types.BlueprintInstanceEvent) bool {
+	if element := ElementStateFromEvent(event); element != nil {
+		p.elements[elementKey(element.Kind, element.Name)] = element
+	}
+
+	if instanceUpdate, ok := event.AsInstanceUpdate(); ok {
+		p.instanceStatus = instanceUpdate.Status
+	}
+
+	if finish, ok := event.AsFinish(); ok {
+		p.instanceStatus = finish.Status
+		p.finished = true
+		p.finish = event
+	}
+
+	return p.finished
+}
+
+// ElementStateFromEvent extracts the state of the resource, link or child blueprint
+// that a deployment event is for, this returns nil for events
+// that are for the blueprint instance as a whole.
+func ElementStateFromEvent(event *types.BlueprintInstanceEvent) *ElementState {
+	if resourceUpdate, ok := event.AsResourceUpdate(); ok {
+		return &ElementState{
+			Kind:           ElementKindResource,
+			Name:           resourceUpdate.ResourceName,
+			Status:         engine.ResourceStatusLabel(resourceUpdate.Status),
+			InProgress:     isResourceInProgress(resourceUpdate.Status),
+			Failed:         isResourceFailure(resourceUpdate.Status),
+			RollingBack:    resourceUpdate.Status == bpcore.ResourceStatusRollingBack,
+			FailureReasons: resourceUpdate.FailureReasons,
+		}
+	}
+
+	if childUpdate, ok := event.AsChildUpdate(); ok {
+		return &ElementState{
+			Kind:           ElementKindChild,
+			Name:           childUpdate.ChildName,
+			Status:         engine.InstanceStatusLabel(childUpdate.Status),
+			InProgress:     engine.IsInstanceStatusInProgress(childUpdate.Status),
+			Failed:         engine.IsInstanceStatusFailure(childUpdate.Status),
+			RollingBack:    engine.IsInstanceStatusRollingBack(childUpdate.Status),
+			FailureReasons: childUpdate.FailureReasons,
+		}
+	}
+
+	if linkUpdate, ok := event.AsLinkUpdate(); ok {
+		return &ElementState{
+			Kind:           ElementKindLink,
+			Name:           linkUpdate.LinkName,
+			Status:         engine.LinkStatusLabel(linkUpdate.Status),
+			InProgress:     isLinkInProgress(linkUpdate.Status),
+			Failed:         isLinkFailure(linkUpdate.Status),
+			RollingBack:    isLinkRollingBack(linkUpdate.Status),
+			FailureReasons: linkUpdate.FailureReasons,
+		}
+	}
+
+	return nil
+}
+
+// String renders the element state as a single line,
+// e.g. "resource ordersTable: create failed (table already exists)".
+func (e *ElementState) String() string {
+	line := fmt.Sprintf("%s %s: %s", e.Kind, e.Name, e.Status)
+	if len(e.FailureReasons) > 0 {
+		line = fmt.Sprintf("%s (%s)", line, strings.Join(e.FailureReasons, "; "))
+	}
+
+	return line
+}
+
+// Elements returns the latest state of each element that has been
+// reported in the deployment sorted by element kind and name.
+func (p *Progress) Elements() []*ElementState {
+	elements := make([]*ElementState, 0, len(p.elements))
+	for _, element := range p.elements {
+		elements = append(elements, element)
+	}
+
+	slices.SortFunc(elements, func(a, b *ElementState) int {
+		if a.Kind != b.Kind {
+			return slices.Index(elementKindOrder, a.Kind) - slices.Index(elementKindOrder, b.Kind)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return elements
+}
+
+// InstanceStatus returns the latest status of the blueprint instance
+// being deployed.
+func (p *Progress) InstanceStatus() bpcore.InstanceStatus {
+	return p.instanceStatus
+}
+
+// Finished determines whether the deployment has finished,
+// either successfully or with failures.
+func (p *Progress) Finished() bool {
+	return p.finished
+}
+
+// Succeeded determines whether the deployment finished successfully.
+// A deployment that was rolled back after a failure is not considered successful.
+func (p *Progress) Succeeded() bool {
+	return p.finished && engine.IsInstanceStatusSuccess(p.instanceStatus)
+}
+
+// FailureReasons returns the reasons reported by the deploy engine
+// for the deployment as a whole failing.
+func (p *Progress) FailureReasons() []string {
+	if p.finish == nil || p.finish.FinishEvent == nil {
+		return nil
+	}
+
+	return p.finish.FinishEvent.FailureReasons
+}
+
+// Err returns an error describing why the deployment failed,
+// this returns nil if the deployment has not finished or succeeded.
+func (p *Progress) Err() error {
+	if !p.finished || p.Succeeded() {
+		return nil
+	}
+
+	status := engine.InstanceStatusLabel(p.instanceStatus)
+	reasons := p.FailureReasons()
+	if len(reasons) == 0 {
+		return fmt.Errorf("deployment finished with status %q", status)
+	}
+
+	return fmt.Errorf("deployment finished with status %q: %s", status, strings.Join(reasons, "; "))
+}
+
+func elementKey(kind ElementKind, name string) string {
+	return string(kind) + ":" + name
+}
+
+func isResourceInProgress(status bpcore.ResourceStatus) bool {
+	return status == bpcore.ResourceStatusCreating ||
+		status == bpcore.ResourceStatusUpdating ||
+		status == bpcore.ResourceStatusDestroying ||
+		status == bpcore.ResourceStatusRollingBack
+}
+
+func isResourceFailure(status bpcore.ResourceStatus) bool {
+	return status == bpcore.ResourceStatusCreateFailed ||
+		status == bpcore.ResourceStatusUpdateFailed ||
+		status == bpcore.ResourceStatusDestroyFailed ||
+		status == bpcore.ResourceStatusRollbackFailed
+}
+
+func isLinkInProgress(status bpcore.LinkStatus) bool {
+	return status == bpcore.LinkStatusCreating ||
+		status == bpcore.LinkStatusUpdating ||
+		status == bpcore.LinkStatusDestroying ||
+		isLinkRollingBack(status)
+}
+
+func isLinkRollingBack(status bpcore.LinkStatus) bool {
+	return status == bpcore.LinkStatusCreateRollingBack ||
+		status == bpcore.LinkStatusUpdateRollingBack ||
+		status == bpcore.LinkStatusDestroyRollingBack
+}
+
+func isLinkFailure(status bpcore.LinkStatus) bool {
+	return status == bpcore.LinkStatusCreateFailed ||
+		status == bpcore.LinkStatusUpdateFailed ||
+		status == bpcore.LinkStatusDestroyFailed ||
+		status == bpcore.LinkStatusCreateRollbackFailed ||
+		status == bpcore.LinkStatusUpdateRollbackFailed ||
+		status == bpcore.LinkStatusDestroyRollbackFailed
+}
@@ -0,0 +1,134 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProgressTestSuite struct {
+	suite.Suite
+}
+
+func TestProgressTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressTestSuite))
+}
+
+func (s *ProgressTestSuite) Test_tracks_latest_status_of_each_element() {
+	progress := NewProgress()
+
+	events := []*types.BlueprintInstanceEvent{
+		instanceUpdateEvent(core.InstanceStatusDeploying),
+		resourceUpdateEvent("ordersTable", core.ResourceStatusCreating),
+		resourceUpdateEvent("ordersQueue", core.ResourceStatusCreating),
+		resourceUpdateEvent("ordersTable", core.ResourceStatusCreated),
+		linkUpdateEvent("ordersQueue::ordersTable", core.LinkStatusCreating),
+	}
+	for _, event := range events {
+		s.Assert().False(progress.Apply(event))
+	}
+
+	elements := progress.Elements()
+	s.Require().Len(elements, 3)
+	s.Assert().Equal("resource ordersQueue: creating", elements[0].String())
+	s.Assert().True(elements[0].InProgress)
+	s.Assert().Equal("resource ordersTable: created", elements[1].String())
+	s.Assert().False(elements[1].InProgress)
+	s.Assert().Equal("link ordersQueue::ordersTable: creating", elements[2].String())
+	s.Assert().Equal(core.InstanceStatusDeploying, progress.InstanceStatus())
+	s.Assert().False(progress.Finished())
+	s.Assert().NoError(progress.Err())
+}
+
+func (s *ProgressTestSuite) Test_successful_deployment() {
+	progress := NewProgress()
+	progress.Apply(resourceUpdateEvent("ordersTable", core.ResourceStatusCreated))
+
+	s.Assert().True(progress.Apply(finishEvent(core.InstanceStatusDeployed)))
+	s.Assert().True(progress.Finished())
+	s.Assert().True(progress.Succeeded())
+	s.Assert().NoError(progress.Err())
+}
+
+func (s *ProgressTestSuite) Test_failed_deployment_reports_failure_reasons() {
+	progress := NewProgress()
+	failed := resourceUpdateEvent("ordersTable", core.ResourceStatusCreateFailed)
+	failed.ResourceUpdateEvent.FailureReasons = []string{"table already exists"}
+	progress.Apply(failed)
+	finish := finishEvent(core.InstanceStatusDeployRollbackComplete)
+	finish.FinishEvent.FailureReasons = []string{"failed to create resources"}
+
+	s.Assert().True(progress.Apply(finish))
+
+	elements := progress.Elements()
+	s.Require().Len(elements, 1)
+	s.Assert().True(elements[0].Failed)
+	s.Assert().Equal(
+		"resource ordersTable: create failed (table already exists)",
+		elements[0].String(),
+	)
+	s.Assert().False(progress.Succeeded())
+	s.Assert().EqualError(
+		progress.Err(),
+		`deployment finished with status "deployment rolled back": failed to create resources`,
+	)
+}
+
+func (s *ProgressTestSuite) Test_rolling_back_elements() {
+	progress := NewProgress()
+	progress.Apply(resourceUpdateEvent("ordersTable", core.ResourceStatusRollingBack))
+
+	elements := progress.Elements()
+	s.Require().Len(elements, 1)
+	s.Assert().True(elements[0].RollingBack)
+	s.Assert().True(elements[0].InProgress)
+}
+
+func instanceUpdateEvent(status core.InstanceStatus) *types.BlueprintInstanceEvent {
+	return &types.BlueprintInstanceEvent{
+		DeployEvent: container.DeployEvent{
+			DeploymentUpdateEvent: &container.DeploymentUpdateMessage{
+				InstanceID: "instance-1",
+				Status:     status,
+			},
+		},
+	}
+}
+
+func resourceUpdateEvent(name string, status core.ResourceStatus) *types.BlueprintInstanceEvent {
+	return &types.BlueprintInstanceEvent{
+		DeployEvent: container.DeployEvent{
+			ResourceUpdateEvent: &container.ResourceDeployUpdateMessage{
+				InstanceID:   "instance-1",
+				ResourceName: name,
+				Status:       status,
+			},
+		},
+	}
+}
+
+func linkUpdateEvent(name string, status core.LinkStatus) *types.BlueprintInstanceEvent {
+	return &types.BlueprintInstanceEvent{
+		DeployEvent: container.DeployEvent{
+			LinkUpdateEvent: &container.LinkDeployUpdateMessage{
+				InstanceID: "instance-1",
+				LinkName:   name,
+				Status:     status,
+			},
+		},
+	}
+}
+
+func finishEvent(status core.InstanceStatus) *types.BlueprintInstanceEvent {
+	return &types.BlueprintInstanceEvent{
+		DeployEvent: container.DeployEvent{
+			FinishEvent: &container.DeploymentFinishedMessage{
+				InstanceID: "instance-1",
+				Status:     status,
+			},
+		},
+	}
+}
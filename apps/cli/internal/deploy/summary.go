@@ -0,0 +1,255 @@
+package deploy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+)
+
+// ElementKind is the kind of blueprint element that a change
+// or deployment progress update applies to.
+type ElementKind string
+
+const (
+	// ElementKindResource is a resource in a blueprint.
+	ElementKindResource ElementKind = "resource"
+	// ElementKindChild is a child blueprint included in a blueprint.
+	ElementKindChild ElementKind = "child"
+	// ElementKindLink is a link between two resources in a blueprint.
+	ElementKindLink ElementKind = "link"
+	// ElementKindExport is an export of a blueprint.
+	ElementKindExport ElementKind = "export"
+)
+
+var elementKindOrder = []ElementKind{
+	ElementKindResource,
+	ElementKindChild,
+	ElementKindLink,
+	ElementKindExport,
+}
+
+// Action is the action that will be taken for an element
+// when a change set is deployed.
+type Action string
+
+const (
+	// ActionCreate is used for elements that will be created.
+	ActionCreate Action = "create"
+	// ActionUpdate is used for existing elements that will be updated in place.
+	ActionUpdate Action = "update"
+	// ActionRecreate is used for existing elements that will be
+	// removed and created again.
+	ActionRecreate Action = "recreate"
+	// ActionRemove is used for existing elements that will be removed.
+	ActionRemove Action = "remove"
+)
+
+var actionSymbols = map[Action]string{
+	ActionCreate:   "+",
+	ActionUpdate:   "~",
+	ActionRecreate: "±",
+	ActionRemove:   "-",
+}
+
+// ElementChange describes the action that will be taken
+// for a single element of a blueprint.
+type ElementChange struct {
+	Kind   ElementKind
+	Name   string
+	Action Action
+	// Fields holds the paths of the fields that will be modified,
+	// this is only populated for resource updates.
+	Fields []string
+}
+
+// String renders the element change in a compact form
+// suitable for displaying in a diff summary.
+func (c ElementChange) String() string {
+	line := fmt.Sprintf("%s %s %s (%s)", actionSymbols[c.Action], c.Kind, c.Name, c.Action)
+	if len(c.Fields) > 0 {
+		line = fmt.Sprintf("%s: %s", line, strings.Join(c.Fields, ", "))
+	}
+
+	return line
+}
+
+// Summary holds a flattened view of the changes in a change set
+// that can be presented to a user before deploying.
+type Summary struct {
+	Changes []ElementChange
+}
+
+// HasChanges determines whether there are any changes to deploy.
+func (s *Summary) HasChanges() bool {
+	return len(s.Changes) > 0
+}
+
+// Count returns the number of changes in the summary with the given action.
+func (s *Summary) Count(action Action) int {
+	count := 0
+	for _, change := range s.Changes {
+		if change.Action == action {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+// Totals renders the number of elements for each action,
+// e.g. "2 to create, 1 to update, 0 to recreate, 0 to remove".
+func (s *Summary) Totals() string {
+	return fmt.Sprintf(
+		"%d to create, %d to update, %d to recreate, %d to remove",
+		s.Count(ActionCreate),
+		s.Count(ActionUpdate),
+		s.Count(ActionRecreate),
+		s.Count(ActionRemove),
+	)
+}
+
+// Summarise produces a summary of the changes that will be applied
+// for a change set. Changes are sorted by element kind and name.
+// Only the top-level elements of a blueprint are included in the summary,
+// changes within child blueprints are reported as an update to the child.
+func Summarise(blueprintChanges *changes.BlueprintChanges) *Summary {
+	summary := &Summary{Changes: []ElementChange{}}
+	if blueprintChanges == nil {
+		return summary
+	}
+
+	for name, resourceChanges := range blueprintChanges.NewResources {
+		summary.Changes = append(summary.Changes, ElementChange{
+			Kind:   ElementKindResource,
+			Name:   name,
+			Action: ActionCreate,
+		})
+		summary.addNewLinks(name, &resourceChanges)
+	}
+
+	for name, resourceChanges := range blueprintChanges.ResourceChanges {
+		if resourceChanges.MustRecreate {
+			summary.Changes = append(summary.Changes, ElementChange{
+				Kind:   ElementKindResource,
+				Name:   name,
+				Action: ActionRecreate,
+			})
+		} else if resourceHasFieldChanges(&resourceChanges) {
+			summary.Changes = append(summary.Changes, ElementChange{
+				Kind:   ElementKindResource,
+				Name:   name,
+				Action: ActionUpdate,
+				Fields: modifiedFields(&resourceChanges),
+			})
+		}
+		summary.addNewLinks(name, &resourceChanges)
+		summary.addLinkChanges(name, &resourceChanges)
+	}
+
+	for _, name := range blueprintChanges.RemovedResources {
+		summary.add(ElementKindResource, name, ActionRemove)
+	}
+
+	for _, name := range blueprintChanges.RemovedLinks {
+		summary.add(ElementKindLink, name, ActionRemove)
+	}
+
+	for name := range blueprintChanges.NewChildren {
+		summary.add(ElementKindChild, name, ActionCreate)
+	}
+
+	for name := range blueprintChanges.ChildChanges {
+		summary.add(ElementKindChild, name, ActionUpdate)
+	}
+
+	for _, name := range blueprintChanges.RecreateChildren {
+		summary.add(ElementKindChild, name, ActionRecreate)
+	}
+
+	for _, name := range blueprintChanges.RemovedChildren {
+		summary.add(ElementKindChild, name, ActionRemove)
+	}
+
+	for name := range blueprintChanges.NewExports {
+		summary.add(ElementKindExport, name, ActionCreate)
+	}
+
+	for name := range blueprintChanges.ExportChanges {
+		summary.add(ElementKindExport, name, ActionUpdate)
+	}
+
+	for _, name := range blueprintChanges.RemovedExports {
+		summary.add(ElementKindExport, name, ActionRemove)
+	}
+
+	slices.SortFunc(summary.Changes, func(a, b ElementChange) int {
+		if a.Kind != b.Kind {
+			return slices.Index(elementKindOrder, a.Kind) - slices.Index(elementKindOrder, b.Kind)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return summary
+}
+
+// add records a change for an element, changes that have already been
+// recorded are skipped as links that are removed can be reported both
+// in the removed links of the blueprint and the removed outbound links
+// of the resource the link is from.
+func (s *Summary) add(kind ElementKind, name string, action Action) {
+	alreadyAdded := slices.ContainsFunc(s.Changes, func(change ElementChange) bool {
+		return change.Kind == kind && change.Name == name && change.Action == action
+	})
+	if alreadyAdded {
+		return
+	}
+
+	s.Changes = append(s.Changes, ElementChange{
+		Kind:   kind,
+		Name:   name,
+		Action: action,
+	})
+}
+
+func (s *Summary) addNewLinks(resourceName string, resourceChanges *provider.Changes) {
+	for linkedTo := range resourceChanges.NewOutboundLinks {
+		s.add(ElementKindLink, bpcore.LogicalLinkName(resourceName, linkedTo), ActionCreate)
+	}
+}
+
+func (s *Summary) addLinkChanges(resourceName string, resourceChanges *provider.Changes) {
+	for linkedTo := range resourceChanges.OutboundLinkChanges {
+		s.add(ElementKindLink, bpcore.LogicalLinkName(resourceName, linkedTo), ActionUpdate)
+	}
+
+	for _, linkName := range resourceChanges.RemovedOutboundLinks {
+		s.add(ElementKindLink, linkName, ActionRemove)
+	}
+}
+
+func resourceHasFieldChanges(resourceChanges *provider.Changes) bool {
+	return len(resourceChanges.ModifiedFields) > 0 ||
+		len(resourceChanges.NewFields) > 0 ||
+		len(resourceChanges.RemovedFields) > 0 ||
+		len(resourceChanges.FieldChangesKnownOnDeploy) > 0
+}
+
+func modifiedFields(resourceChanges *provider.Changes) []string {
+	fields := []string{}
+	for _, field := range resourceChanges.ModifiedFields {
+		fields = append(fields, field.FieldPath)
+	}
+
+	for _, field := range resourceChanges.NewFields {
+		fields = append(fields, field.FieldPath)
+	}
+
+	fields = append(fields, resourceChanges.RemovedFields...)
+	fields = append(fields, resourceChanges.FieldChangesKnownOnDeploy...)
+	slices.Sort(fields)
+	return slices.Compact(fields)
+}
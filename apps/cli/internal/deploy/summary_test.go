@@ -0,0 +1,125 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/stretchr/testify/suite"
+)
+
+type SummaryTestSuite struct {
+	suite.Suite
+}
+
+func TestSummaryTestSuite(t *testing.T) {
+	suite.Run(t, new(SummaryTestSuite))
+}
+
+func (s *SummaryTestSuite) Test_summarises_changes_sorted_by_kind_and_name() {
+	summary := Summarise(&changes.BlueprintChanges{
+		NewResources: map[string]provider.Changes{
+			"ordersQueue": {
+				NewOutboundLinks: map[string]provider.LinkChanges{
+					"ordersHandler": {},
+				},
+			},
+		},
+		ResourceChanges: map[string]provider.Changes{
+			"ordersTable": {
+				ModifiedFields: []provider.FieldChange{
+					{FieldPath: "spec.billingMode"},
+				},
+				NewFields: []provider.FieldChange{
+					{FieldPath: "spec.tags"},
+				},
+			},
+			"ordersBucket": {
+				MustRecreate: true,
+			},
+			"ordersHandler": {
+				RemovedOutboundLinks: []string{"ordersHandler::legacyTable"},
+			},
+		},
+		RemovedResources: []string{"legacyTable"},
+		NewChildren: map[string]changes.NewBlueprintDefinition{
+			"networking": {},
+		},
+		RemovedExports: []string{"legacyTableName"},
+	})
+
+	s.Assert().Equal(
+		[]ElementChange{
+			{Kind: ElementKindResource, Name: "legacyTable", Action: ActionRemove},
+			{Kind: ElementKindResource, Name: "ordersBucket", Action: ActionRecreate},
+			{Kind: ElementKindResource, Name: "ordersQueue", Action: ActionCreate},
+			{
+				Kind:   ElementKindResource,
+				Name:   "ordersTable",
+				Action: ActionUpdate,
+				Fields: []string{"spec.billingMode", "spec.tags"},
+			},
+			{Kind: ElementKindChild, Name: "networking", Action: ActionCreate},
+			{Kind: ElementKindLink, Name: "ordersHandler::legacyTable", Action: ActionRemove},
+			{Kind: ElementKindLink, Name: "ordersQueue::ordersHandler", Action: ActionCreate},
+			{Kind: ElementKindExport, Name: "legacyTableName", Action: ActionRemove},
+		},
+		summary.Changes,
+	)
+	s.Assert().True(summary.HasChanges())
+	s.Assert().Equal("3 to create, 1 to update, 1 to recreate, 3 to remove", summary.Totals())
+}
+
+func (s *SummaryTestSuite) Test_resources_without_field_changes_are_not_included() {
+	summary := Summarise(&changes.BlueprintChanges{
+		ResourceChanges: map[string]provider.Changes{
+			"ordersTable": {},
+		},
+	})
+
+	s.Assert().False(summary.HasChanges())
+	s.Assert().Equal("0 to create, 0 to update, 0 to recreate, 0 to remove", summary.Totals())
+}
+
+func (s *SummaryTestSuite) Test_removed_links_are_only_included_once() {
+	summary := Summarise(&changes.BlueprintChanges{
+		ResourceChanges: map[string]provider.Changes{
+			"ordersHandler": {
+				RemovedOutboundLinks: []string{"ordersHandler::legacyTable"},
+			},
+		},
+		RemovedLinks: []string{"ordersHandler::legacyTable", "ordersHandler::legacyQueue"},
+	})
+
+	s.Assert().Equal(
+		[]ElementChange{
+			{Kind: ElementKindLink, Name: "ordersHandler::legacyQueue", Action: ActionRemove},
+			{Kind: ElementKindLink, Name: "ordersHandler::legacyTable", Action: ActionRemove},
+		},
+		summary.Changes,
+	)
+	s.Assert().Equal("0 to create, 0 to update, 0 to recreate, 2 to remove", summary.Totals())
+}
+
+func (s *SummaryTestSuite) Test_summarises_nil_changes_as_empty() {
+	summary := Summarise(nil)
+
+	s.Assert().False(summary.HasChanges())
+	s.Assert().Empty(summary.Changes)
+}
+
+func (s *SummaryTestSuite) Test_renders_element_change() {
+	s.Assert().Equal(
+		"+ resource ordersTable (create)",
+		ElementChange{Kind: ElementKindResource, Name: "ordersTable", Action: ActionCreate}.String(),
+	)
+	s.Assert().Equal(
+		"~ resource ordersTable (update): spec.billingMode, spec.tags",
+		ElementChange{
+			Kind:   ElementKindResource,
+			Name:   "ordersTable",
+			Action: ActionUpdate,
+			Fields: []string{"spec.billingMode", "spec.tags"},
+		}.String(),
+	)
+}
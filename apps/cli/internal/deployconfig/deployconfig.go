@@ -0,0 +1,64 @@
+package deployconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// Load reads the deployment configuration file at the given path.
+// The file holds blueprint variable overrides along with provider, transformer
+// and context variable configuration that is sent to the deploy engine
+// for validation, change staging and deployment.
+//
+// When the file does not exist and allowMissing is true, an empty
+// configuration is returned; this is useful when the path is the default
+// location that the user has not explicitly asked for.
+func Load(path string, allowMissing bool) (*types.BlueprintOperationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && allowMissing {
+			return Empty(), nil
+		}
+		return nil, fmt.Errorf("reading deploy config %s: %w", path, err)
+	}
+
+	config := Empty()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing deploy config %s: %w", path, err)
+	}
+	fillEmpty(config)
+
+	return config, nil
+}
+
+// Empty returns a deployment configuration with no values set
+// where all the maps are initialised.
+func Empty() *types.BlueprintOperationConfig {
+	return &types.BlueprintOperationConfig{
+		Providers:          map[string]map[string]*core.ScalarValue{},
+		Transformers:       map[string]map[string]*core.ScalarValue{},
+		ContextVariables:   map[string]*core.ScalarValue{},
+		BlueprintVariables: map[string]*core.ScalarValue{},
+	}
+}
+
+// fillEmpty makes sure sections explicitly set to null in the
+// config file do not leave nil maps behind.
+func fillEmpty(config *types.BlueprintOperationConfig) {
+	if config.Providers == nil {
+		config.Providers = map[string]map[string]*core.ScalarValue{}
+	}
+	if config.Transformers == nil {
+		config.Transformers = map[string]map[string]*core.ScalarValue{}
+	}
+	if config.ContextVariables == nil {
+		config.ContextVariables = map[string]*core.ScalarValue{}
+	}
+	if config.BlueprintVariables == nil {
+		config.BlueprintVariables = map[string]*core.ScalarValue{}
+	}
+}
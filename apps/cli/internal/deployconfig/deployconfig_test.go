@@ -0,0 +1,66 @@
+package deployconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeployConfigTestSuite struct {
+	suite.Suite
+}
+
+func TestDeployConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(DeployConfigTestSuite))
+}
+
+func (s *DeployConfigTestSuite) Test_loads_blueprint_variables_and_provider_config() {
+	path := filepath.Join(s.T().TempDir(), "celerity.deploy.json")
+	content := `{
+  "blueprintVariables": {
+    "environment": "staging",
+    "instanceCount": 3
+  },
+  "providers": {
+    "aws": {
+      "region": "eu-west-2"
+    }
+  }
+}`
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+
+	config, err := Load(path, false)
+	s.Require().NoError(err)
+	s.Assert().Equal("staging", core.StringValueFromScalar(config.BlueprintVariables["environment"]))
+	s.Assert().Equal(3, core.IntValueFromScalar(config.BlueprintVariables["instanceCount"]))
+	s.Assert().Equal("eu-west-2", core.StringValueFromScalar(config.Providers["aws"]["region"]))
+	s.Assert().NotNil(config.Transformers)
+	s.Assert().NotNil(config.ContextVariables)
+}
+
+func (s *DeployConfigTestSuite) Test_missing_file_returns_empty_config_when_allowed() {
+	path := filepath.Join(s.T().TempDir(), "celerity.deploy.json")
+
+	config, err := Load(path, true)
+	s.Require().NoError(err)
+	s.Assert().Empty(config.BlueprintVariables)
+	s.Assert().Empty(config.Providers)
+}
+
+func (s *DeployConfigTestSuite) Test_missing_file_returns_error_when_not_allowed() {
+	path := filepath.Join(s.T().TempDir(), "celerity.deploy.json")
+
+	_, err := Load(path, false)
+	s.Assert().ErrorIs(err, os.ErrNotExist)
+}
+
+func (s *DeployConfigTestSuite) Test_invalid_json_returns_error() {
+	path := filepath.Join(s.T().TempDir(), "celerity.deploy.json")
+	s.Require().NoError(os.WriteFile(path, []byte(`{"blueprintVariables": `), 0o644))
+
+	_, err := Load(path, true)
+	s.Assert().ErrorContains(err, "parsing deploy config")
+}
@@ -0,0 +1,207 @@
+package docs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+)
+
+// DefaultCacheDir is the directory documentation is loaded from for plugins
+// that do not include it in their release archives.
+// The directory is filled by Sync with the documentation published for
+// installed plugins, documentation produced by the plugin docs generator
+// for plugins that are not installed can also be placed in it by hand.
+// Each file holds the docs for a single plugin.
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "celerity", "plugin-docs"), nil
+}
+
+// Entry is a resource type in the catalog along with
+// the plugin that provides it.
+type Entry struct {
+	Plugin   *PluginDocs
+	Resource *ResourceDocs
+}
+
+// Catalog holds the documentation for the resource types provided
+// by the plugins that are available locally.
+type Catalog struct {
+	// Entries are ordered by resource type.
+	Entries []*Entry
+	// Skipped holds the errors for the docs files that could not be loaded.
+	Skipped []error
+}
+
+// LoadCatalog loads the documentation for the latest installed version of
+// each plugin in the plugin directory, followed by the documentation files
+// in the cache directory for plugins that are not installed.
+// Files that can not be loaded are skipped and recorded in the catalog
+// so a single broken file does not prevent looking up the rest of the docs.
+func LoadCatalog(layout plugins.Layout, cacheDir string) (*Catalog, error) {
+	installed, err := layout.List()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	// Installed plugins are ordered from the oldest to the newest version,
+	// the newest version of each plugin takes precedence.
+	for i := len(installed) - 1; i >= 0; i -= 1 {
+		plugin := installed[i]
+		path := layout.DocsPath(plugin.Type, plugin.ID, plugin.Version)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+
+	cachedPaths, err := cachedDocsPaths(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, cachedPaths...)
+
+	catalog := &Catalog{}
+	loaded := map[string]bool{}
+	for _, path := range paths {
+		pluginDocs, err := loadPluginDocs(path)
+		if err != nil {
+			catalog.Skipped = append(catalog.Skipped, err)
+			continue
+		}
+
+		if loaded[pluginDocs.ID] {
+			continue
+		}
+		loaded[pluginDocs.ID] = true
+
+		for _, resource := range pluginDocs.Resources {
+			catalog.Entries = append(catalog.Entries, &Entry{
+				Plugin:   pluginDocs,
+				Resource: resource,
+			})
+		}
+	}
+
+	slices.SortStableFunc(catalog.Entries, func(a, b *Entry) int {
+		return strings.Compare(a.Resource.Type, b.Resource.Type)
+	})
+	return catalog, nil
+}
+
+func cachedDocsPaths(cacheDir string) ([]string, error) {
+	if cacheDir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		paths = append(paths, filepath.Join(cacheDir, entry.Name()))
+	}
+
+	return paths, nil
+}
+
+func loadPluginDocs(path string) (*PluginDocs, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePluginDocs(content, path)
+}
+
+func parsePluginDocs(content []byte, source string) (*PluginDocs, error) {
+	pluginDocs := &PluginDocs{}
+	if err := json.Unmarshal(content, pluginDocs); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin docs in %s: %w", source, err)
+	}
+
+	if pluginDocs.ID == "" {
+		return nil, fmt.Errorf("plugin docs in %s are missing the plugin ID", source)
+	}
+	pluginDocs.Source = source
+
+	pluginDocs.Resources = slices.DeleteFunc(pluginDocs.Resources, func(resource *ResourceDocs) bool {
+		return resource == nil || resource.Type == ""
+	})
+	return pluginDocs, nil
+}
+
+// Lookup finds the entry for a resource type, ignoring case.
+func (c *Catalog) Lookup(resourceType string) (*Entry, bool) {
+	for _, entry := range c.Entries {
+		if strings.EqualFold(entry.Resource.Type, resourceType) {
+			return entry, true
+		}
+	}
+
+	return nil, false
+}
+
+// Search finds the entries that match the query, ignoring case.
+// Resource types that start with the query are ranked first, followed by
+// resource types that contain the query and then the resource types with
+// a label or summary that contains the query.
+// All entries are returned for an empty query.
+func (c *Catalog) Search(query string) []*Entry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return slices.Clone(c.Entries)
+	}
+
+	ranked := make([][]*Entry, 3)
+	for _, entry := range c.Entries {
+		resourceType := strings.ToLower(entry.Resource.Type)
+		switch {
+		case strings.HasPrefix(resourceType, query):
+			ranked[0] = append(ranked[0], entry)
+		case strings.Contains(resourceType, query):
+			ranked[1] = append(ranked[1], entry)
+		case strings.Contains(strings.ToLower(entry.Resource.Label), query) ||
+			strings.Contains(strings.ToLower(entry.Resource.Summary), query):
+			ranked[2] = append(ranked[2], entry)
+		}
+	}
+
+	return slices.Concat(ranked...)
+}
+
+// Links finds the documentation for the links the resource type
+// can be a part of.
+func (e *Entry) Links() []*LinkDocs {
+	links := []*LinkDocs{}
+	for _, link := range e.Plugin.Links {
+		if link == nil {
+			continue
+		}
+		typeA, typeB, _ := strings.Cut(link.Type, "::")
+		if typeA == e.Resource.Type || typeB == e.Resource.Type {
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,288 @@
+package docs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+	"github.com/stretchr/testify/suite"
+)
+
+type DocsTestSuite struct {
+	suite.Suite
+	layout   plugins.Layout
+	cacheDir string
+	aws      plugins.ID
+}
+
+func TestDocsTestSuite(t *testing.T) {
+	suite.Run(t, new(DocsTestSuite))
+}
+
+func (s *DocsTestSuite) SetupTest() {
+	s.layout = plugins.Layout{Dir: s.T().TempDir()}
+	s.cacheDir = s.T().TempDir()
+	s.aws = plugins.ID{Host: plugins.DefaultRegistryHost, Namespace: "bluelink", Name: "aws"}
+}
+
+func (s *DocsTestSuite) Test_loads_docs_for_latest_installed_version_before_cache() {
+	s.install("1.0.0", `{"id": "bluelink/aws", "version": "1.0.0", "resources": [{"type": "aws/s3/bucket"}]}`)
+	s.install("1.1.0", awsDocs)
+	s.cache("aws.json", `{"id": "bluelink/aws", "version": "0.9.0", "resources": [{"type": "aws/sqs/queue"}]}`)
+	s.cache("gcp.json", `{"id": "bluelink/gcp", "resources": [{"type": "gcp/storage/bucket"}, {"label": "No type"}]}`)
+	s.cache("broken.json", `{"id": `)
+	s.cache("notes.txt", "not docs")
+
+	catalog, err := LoadCatalog(s.layout, s.cacheDir)
+	s.Require().NoError(err)
+
+	types := []string{}
+	for _, entry := range catalog.Entries {
+		types = append(types, entry.Resource.Type)
+	}
+	s.Assert().Equal([]string{"aws/dynamodb/table", "aws/lambda/function", "gcp/storage/bucket"}, types)
+	s.Require().Len(catalog.Skipped, 1)
+	s.Assert().ErrorContains(catalog.Skipped[0], "failed to parse plugin docs in "+filepath.Join(s.cacheDir, "broken.json"))
+
+	entry, ok := catalog.Lookup("AWS/Lambda/Function")
+	s.Require().True(ok)
+	s.Assert().Equal("1.1.0", entry.Plugin.Version)
+
+	_, ok = catalog.Lookup("aws/s3/bucket")
+	s.Assert().False(ok)
+}
+
+func (s *DocsTestSuite) Test_searches_resource_types_before_labels_and_summaries() {
+	s.install("1.1.0", awsDocs)
+	s.cache("gcp.json", `{"id": "bluelink/gcp", "resources": [
+		{"type": "gcp/functions/function", "label": "Cloud Function"},
+		{"type": "gcp/firestore/database", "summary": "A serverless NoSQL database, an alternative to DynamoDB."}
+	]}`)
+
+	catalog, err := LoadCatalog(s.layout, s.cacheDir)
+	s.Require().NoError(err)
+
+	s.Assert().Equal([]string{"aws/lambda/function", "gcp/functions/function"}, searchTypes(catalog, "function"))
+	s.Assert().Equal([]string{"gcp/firestore/database", "gcp/functions/function"}, searchTypes(catalog, "gcp/f"))
+	s.Assert().Equal([]string{"gcp/functions/function"}, searchTypes(catalog, "cloud func"))
+	s.Assert().Equal([]string{"aws/dynamodb/table", "gcp/firestore/database"}, searchTypes(catalog, "DynamoDB"))
+	s.Assert().Len(searchTypes(catalog, " "), 4)
+	s.Assert().Empty(searchTypes(catalog, "kubernetes"))
+}
+
+func (s *DocsTestSuite) Test_renders_spec_fields_examples_and_links() {
+	s.install("1.1.0", awsDocs)
+	catalog, err := LoadCatalog(s.layout, "")
+	s.Require().NoError(err)
+
+	entry, ok := catalog.Lookup("aws/lambda/function")
+	s.Require().True(ok)
+	s.Assert().Equal(expectedLambdaDocs, Render(entry, RenderOptions{Width: 60}))
+}
+
+func (s *DocsTestSuite) Test_syncs_docs_for_latest_installed_versions_to_cache() {
+	gcp := plugins.ID{Host: plugins.DefaultRegistryHost, Namespace: "bluelink", Name: "gcp"}
+	azure := plugins.ID{Host: plugins.DefaultRegistryHost, Namespace: "bluelink", Name: "azure"}
+	celerity := plugins.ID{Host: plugins.DefaultRegistryHost, Namespace: "bluelink", Name: "celerity"}
+	s.install("1.1.0", awsDocs)
+	s.installBinary(plugins.TypeProvider, gcp, "1.0.0")
+	s.installBinary(plugins.TypeProvider, gcp, "1.2.0")
+	s.installBinary(plugins.TypeProvider, azure, "1.0.0")
+	s.installBinary(plugins.TypeProvider, celerity, "0.1.0")
+	// Docs downloaded before a version that includes them was installed.
+	staleAWSDocs := CachePath(s.cacheDir, plugins.TypeProvider, s.aws)
+	s.Require().NoError(os.WriteFile(staleAWSDocs, []byte(`{"id": "bluelink/aws", "version": "1.0.0"}`), 0o644))
+
+	downloader := &fakeDownloader{
+		docs: map[string]string{
+			"bluelink/gcp@1.2.0":      `{"id": "bluelink/gcp", "version": "1.2.0", "resources": [{"type": "gcp/storage/bucket"}]}`,
+			"bluelink/celerity@0.1.0": `{"resources": []}`,
+		},
+		downloaded: []string{},
+	}
+	results, err := Sync(context.Background(), s.layout, s.cacheDir, downloader)
+	s.Require().NoError(err)
+
+	statuses := map[string]SyncStatus{}
+	for _, result := range results {
+		statuses[result.Plugin.ID.Name] = result.Status
+	}
+	s.Assert().Equal(map[string]SyncStatus{
+		"aws":      SyncStatusIncluded,
+		"gcp":      SyncStatusDownloaded,
+		"azure":    SyncStatusNotPublished,
+		"celerity": SyncStatusFailed,
+	}, statuses)
+	s.Assert().ElementsMatch([]string{"bluelink/gcp@1.2.0", "bluelink/azure@1.0.0", "bluelink/celerity@0.1.0"}, downloader.downloaded)
+
+	cached, err := os.ReadDir(s.cacheDir)
+	s.Require().NoError(err)
+	s.Require().Len(cached, 1)
+	s.Assert().Equal(CachePath(s.cacheDir, plugins.TypeProvider, gcp), filepath.Join(s.cacheDir, cached[0].Name()))
+
+	catalog, err := LoadCatalog(s.layout, s.cacheDir)
+	s.Require().NoError(err)
+	entry, ok := catalog.Lookup("gcp/storage/bucket")
+	s.Require().True(ok)
+	s.Assert().Equal("1.2.0", entry.Plugin.Version)
+}
+
+func (s *DocsTestSuite) Test_caches_docs_separately_for_each_plugin_type() {
+	s.installBinary(plugins.TypeProvider, s.aws, "1.0.0")
+	s.installBinary(plugins.TypeTransformer, s.aws, "2.0.0")
+
+	downloader := &fakeDownloader{
+		docs: map[string]string{
+			"bluelink/aws@1.0.0": `{"id": "bluelink/aws", "version": "1.0.0", "resources": [{"type": "aws/sqs/queue"}]}`,
+			"bluelink/aws@2.0.0": `{"id": "bluelink/aws", "version": "2.0.0", "resources": [{"type": "aws/s3/bucket"}]}`,
+		},
+		downloaded: []string{},
+	}
+	results, err := Sync(context.Background(), s.layout, s.cacheDir, downloader)
+	s.Require().NoError(err)
+	s.Require().Len(results, 2)
+	for _, result := range results {
+		s.Assert().Equal(SyncStatusDownloaded, result.Status)
+	}
+
+	providerDocs, err := os.ReadFile(CachePath(s.cacheDir, plugins.TypeProvider, s.aws))
+	s.Require().NoError(err)
+	s.Assert().Contains(string(providerDocs), "aws/sqs/queue")
+	transformerDocs, err := os.ReadFile(CachePath(s.cacheDir, plugins.TypeTransformer, s.aws))
+	s.Require().NoError(err)
+	s.Assert().Contains(string(transformerDocs), "aws/s3/bucket")
+}
+
+func (s *DocsTestSuite) install(version string, content string) {
+	s.installBinary(plugins.TypeProvider, s.aws, version)
+	s.Require().NoError(os.WriteFile(
+		s.layout.DocsPath(plugins.TypeProvider, s.aws, version),
+		[]byte(content),
+		0o644,
+	))
+}
+
+func (s *DocsTestSuite) installBinary(pluginType plugins.Type, id plugins.ID, version string) {
+	path := s.layout.BinaryPath(pluginType, id, version)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte("binary"), 0o755))
+}
+
+func (s *DocsTestSuite) cache(name string, content string) {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cacheDir, name), []byte(content), 0o644))
+}
+
+type fakeDownloader struct {
+	docs       map[string]string
+	downloaded []string
+}
+
+func (d *fakeDownloader) DownloadDocs(ctx context.Context, plugin *plugins.Installed) ([]byte, error) {
+	key := plugin.ID.Namespace + "/" + plugin.ID.Name + "@" + plugin.Version
+	d.downloaded = append(d.downloaded, key)
+	content, ok := d.docs[key]
+	if !ok {
+		return nil, errors.Join(plugins.ErrDocsNotPublished, errors.New(key))
+	}
+	return []byte(content), nil
+}
+
+func searchTypes(catalog *Catalog, query string) []string {
+	types := []string{}
+	for _, entry := range catalog.Search(query) {
+		types = append(types, entry.Resource.Type)
+	}
+	return types
+}
+
+const awsDocs = `{
+	"id": "bluelink/aws",
+	"version": "1.1.0",
+	"resources": [
+		{
+			"type": "aws/lambda/function",
+			"label": "AWS Lambda Function",
+			"description": "The resource type used to define a Lambda function that runs code in response to events without provisioning servers.",
+			"specification": {
+				"idField": "arn",
+				"schema": {
+					"type": "object",
+					"required": ["functionName"],
+					"attributes": {
+						"functionName": {
+							"type": "string",
+							"description": "The name of the function.",
+							"mustRecreate": true,
+							"examples": ["orders-handler"]
+						},
+						"timeout": {"type": "integer", "default": 3},
+						"arn": {"type": "string", "computed": true},
+						"environment": {
+							"type": "object",
+							"nullable": true,
+							"attributes": {
+								"variables": {"type": "map", "mapValues": {"type": "string"}}
+							}
+						},
+						"layers": {"type": "array", "items": {"type": "string"}}
+					}
+				}
+			},
+			"examples": ["` + "```yaml" + `\nresources:\n  ordersHandler:\n    type: aws/lambda/function\n` + "```" + `"],
+			"canLinkTo": ["aws/dynamodb/table"]
+		},
+		{
+			"type": "aws/dynamodb/table",
+			"label": "AWS DynamoDB Table"
+		}
+	],
+	"links": [
+		{
+			"type": "aws/lambda/function::aws/dynamodb/table",
+			"summary": "Grants the function access to the table."
+		}
+	]
+}`
+
+const expectedLambdaDocs = "aws/lambda/function\n" +
+	"AWS Lambda Function · provided by bluelink/aws v1.1.0\n" +
+	"\n" +
+	"The resource type used to define a Lambda function that runs\n" +
+	"code in response to events without provisioning servers.\n" +
+	"\n" +
+	"SPECIFICATION\n" +
+	"\n" +
+	"  arn  string, computed\n" +
+	"\n" +
+	"  environment  object, nullable\n" +
+	"\n" +
+	"  environment.variables  map[string]\n" +
+	"\n" +
+	"  functionName  string, required, must recreate\n" +
+	"    The name of the function.\n" +
+	"    Examples: \"orders-handler\"\n" +
+	"\n" +
+	"  layers  array[string]\n" +
+	"\n" +
+	"  timeout  integer\n" +
+	"    Default: 3\n" +
+	"\n" +
+	"  The ID of a deployed resource is held in the \"arn\" field.\n" +
+	"\n" +
+	"EXAMPLES\n" +
+	"\n" +
+	"  ```yaml\n" +
+	"  resources:\n" +
+	"    ordersHandler:\n" +
+	"      type: aws/lambda/function\n" +
+	"  ```\n" +
+	"\n" +
+	"LINKS\n" +
+	"\n" +
+	"  Can link to: aws/dynamodb/table\n" +
+	"\n" +
+	"  aws/lambda/function::aws/dynamodb/table\n" +
+	"    Grants the function access to the table.\n"
@@ -0,0 +1,60 @@
+package docs
+
+// PluginDocs holds the documentation for a provider plugin in the
+// JSON format produced by the plugin docs generator.
+// Only the parts of the format that are rendered by the CLI are included.
+type PluginDocs struct {
+	ID          string          `json:"id"`
+	DisplayName string          `json:"displayName"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Resources   []*ResourceDocs `json:"resources"`
+	Links       []*LinkDocs     `json:"links"`
+	// Source is the path of the file the documentation was loaded from.
+	Source string `json:"-"`
+}
+
+// ResourceDocs holds the documentation for a resource type
+// provided by a plugin.
+type ResourceDocs struct {
+	Type          string        `json:"type"`
+	Label         string        `json:"label"`
+	Summary       string        `json:"summary"`
+	Description   string        `json:"description"`
+	Specification *ResourceSpec `json:"specification"`
+	Examples      []string      `json:"examples"`
+	CanLinkTo     []string      `json:"canLinkTo"`
+}
+
+// ResourceSpec holds the schema for the spec of a resource type.
+type ResourceSpec struct {
+	Schema *Schema `json:"schema"`
+	// IDField is the field in the spec that holds the ID of a resource
+	// once it has been deployed.
+	IDField string `json:"idField"`
+}
+
+// Schema describes a value in the spec of a resource type.
+type Schema struct {
+	Type         string             `json:"type"`
+	Label        string             `json:"label"`
+	Description  string             `json:"description"`
+	Nullable     bool               `json:"nullable"`
+	Computed     bool               `json:"computed"`
+	MustRecreate bool               `json:"mustRecreate"`
+	Default      any                `json:"default"`
+	Examples     []any              `json:"examples"`
+	Attributes   map[string]*Schema `json:"attributes"`
+	Required     []string           `json:"required"`
+	Items        *Schema            `json:"items"`
+	MapValues    *Schema            `json:"mapValues"`
+	OneOf        []*Schema          `json:"oneOf"`
+}
+
+// LinkDocs holds the documentation for a link between two resource types,
+// the type is in the form "{resourceTypeA}::{resourceTypeB}".
+type LinkDocs struct {
+	Type        string `json:"type"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,324 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	defaultWidth = 80
+	// maxSchemaDepth guards against recursive schemas
+	// when flattening the spec into a list of fields.
+	maxSchemaDepth = 10
+)
+
+// RenderOptions holds the options for rendering resource type documentation.
+type RenderOptions struct {
+	// Width is the width text is wrapped to,
+	// this defaults to 80 columns when not set.
+	Width int
+	// Styled renders headings and field names with colours and
+	// emphasis for display in a terminal.
+	Styled bool
+}
+
+type renderStyles struct {
+	heading lipgloss.Style
+	title   lipgloss.Style
+	field   lipgloss.Style
+	muted   lipgloss.Style
+}
+
+func newRenderStyles(styled bool) *renderStyles {
+	if !styled {
+		plain := lipgloss.NewStyle()
+		return &renderStyles{heading: plain, title: plain, field: plain, muted: plain}
+	}
+
+	return &renderStyles{
+		heading: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#5882e2")),
+		title:   lipgloss.NewStyle().Bold(true),
+		field:   lipgloss.NewStyle().Foreground(lipgloss.Color("#2b63e3")),
+		muted:   lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280")),
+	}
+}
+
+// Render renders the documentation for the resource type in an entry
+// as text for display in a terminal.
+// The spec schema is flattened into a list of fields with paths relative to
+// the resource spec, e.g. "environment.variables", array items are
+// represented by "[]" and map values by "<key>".
+func Render(entry *Entry, opts RenderOptions) string {
+	width := opts.Width
+	if width <= 0 {
+		width = defaultWidth
+	}
+	styles := newRenderStyles(opts.Styled)
+	resource := entry.Resource
+
+	sb := &strings.Builder{}
+	sb.WriteString(styles.title.Render(resource.Type))
+	sb.WriteString("\n")
+	sb.WriteString(styles.muted.Render(pluginLine(entry)))
+	sb.WriteString("\n")
+
+	description := resource.Description
+	if strings.TrimSpace(description) == "" {
+		description = resource.Summary
+	}
+	if strings.TrimSpace(description) != "" {
+		sb.WriteString("\n")
+		writeWrapped(sb, description, width, "")
+	}
+
+	renderSpec(sb, resource.Specification, width, styles)
+	renderExamples(sb, resource.Examples, styles)
+	renderLinks(sb, entry, width, styles)
+	return sb.String()
+}
+
+func pluginLine(entry *Entry) string {
+	parts := []string{}
+	if entry.Resource.Label != "" {
+		parts = append(parts, entry.Resource.Label)
+	}
+
+	plugin := entry.Plugin.ID
+	if entry.Plugin.Version != "" {
+		plugin = fmt.Sprintf("%s v%s", plugin, strings.TrimPrefix(entry.Plugin.Version, "v"))
+	}
+	parts = append(parts, "provided by "+plugin)
+	return strings.Join(parts, " · ")
+}
+
+func renderSpec(sb *strings.Builder, spec *ResourceSpec, width int, styles *renderStyles) {
+	if spec == nil || spec.Schema == nil {
+		return
+	}
+
+	writeHeading(sb, "Specification", styles)
+	fields := []*specField{}
+	collectFields(&fields, spec.Schema, "", 0)
+	if len(fields) == 0 {
+		sb.WriteString("  The resource type does not have any spec fields.\n")
+	}
+
+	for i, field := range fields {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		renderField(sb, field, width, styles)
+	}
+
+	if spec.IDField != "" {
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  The ID of a deployed resource is held in the %q field.\n", spec.IDField))
+	}
+}
+
+type specField struct {
+	path     string
+	schema   *Schema
+	required bool
+}
+
+func collectFields(fields *[]*specField, schema *Schema, prefix string, depth int) {
+	if schema == nil || depth >= maxSchemaDepth {
+		return
+	}
+
+	switch {
+	case len(schema.OneOf) > 0:
+		for _, option := range schema.OneOf {
+			collectFields(fields, option, prefix, depth+1)
+		}
+	case schema.Type == "array" && schema.Items != nil:
+		collectFields(fields, schema.Items, prefix+"[]", depth+1)
+	case schema.Type == "map" && schema.MapValues != nil:
+		collectFields(fields, schema.MapValues, prefix+".<key>", depth+1)
+	case schema.Type == "object":
+		names := make([]string, 0, len(schema.Attributes))
+		for name := range schema.Attributes {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			attribute := schema.Attributes[name]
+			// Options of a union can share attributes,
+			// which are only listed once.
+			if attribute == nil || slices.ContainsFunc(*fields, func(field *specField) bool {
+				return field.path == path
+			}) {
+				continue
+			}
+			*fields = append(*fields, &specField{
+				path:     path,
+				schema:   attribute,
+				required: slices.Contains(schema.Required, name),
+			})
+			collectFields(fields, attribute, path, depth+1)
+		}
+	}
+}
+
+func renderField(sb *strings.Builder, field *specField, width int, styles *renderStyles) {
+	schema := field.schema
+	sb.WriteString("  ")
+	sb.WriteString(styles.field.Render(field.path))
+	sb.WriteString("  ")
+	sb.WriteString(styles.muted.Render(strings.Join(fieldTraits(field), ", ")))
+	sb.WriteString("\n")
+
+	if strings.TrimSpace(schema.Description) != "" {
+		writeWrapped(sb, schema.Description, width, "    ")
+	}
+
+	if schema.Default != nil {
+		sb.WriteString(fmt.Sprintf("    Default: %s\n", formatValue(schema.Default)))
+	}
+
+	if len(schema.Examples) > 0 {
+		examples := make([]string, 0, len(schema.Examples))
+		for _, example := range schema.Examples {
+			examples = append(examples, formatValue(example))
+		}
+		sb.WriteString(fmt.Sprintf("    Examples: %s\n", strings.Join(examples, ", ")))
+	}
+}
+
+func fieldTraits(field *specField) []string {
+	traits := []string{typeName(field.schema, 0)}
+	if field.required {
+		traits = append(traits, "required")
+	}
+	if field.schema.Nullable {
+		traits = append(traits, "nullable")
+	}
+	if field.schema.Computed {
+		traits = append(traits, "computed")
+	}
+	if field.schema.MustRecreate {
+		traits = append(traits, "must recreate")
+	}
+	return traits
+}
+
+func typeName(schema *Schema, depth int) string {
+	if schema == nil || depth >= maxSchemaDepth {
+		return "any"
+	}
+
+	switch {
+	case len(schema.OneOf) > 0:
+		options := make([]string, 0, len(schema.OneOf))
+		for _, option := range schema.OneOf {
+			options = append(options, typeName(option, depth+1))
+		}
+		return strings.Join(options, " | ")
+	case schema.Type == "array":
+		return "array[" + typeName(schema.Items, depth+1) + "]"
+	case schema.Type == "map":
+		return "map[" + typeName(schema.MapValues, depth+1) + "]"
+	case schema.Type == "":
+		return "any"
+	}
+
+	return schema.Type
+}
+
+func formatValue(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+func renderExamples(sb *strings.Builder, examples []string, styles *renderStyles) {
+	if len(examples) == 0 {
+		return
+	}
+
+	writeHeading(sb, "Examples", styles)
+	for i, example := range examples {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		// Examples are markdown documents that usually hold
+		// code blocks, so they are indented but never wrapped.
+		for _, line := range strings.Split(strings.TrimSpace(example), "\n") {
+			if strings.TrimSpace(line) == "" {
+				sb.WriteString("\n")
+				continue
+			}
+			sb.WriteString("  ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+}
+
+func renderLinks(sb *strings.Builder, entry *Entry, width int, styles *renderStyles) {
+	links := entry.Links()
+	if len(links) == 0 && len(entry.Resource.CanLinkTo) == 0 {
+		return
+	}
+
+	writeHeading(sb, "Links", styles)
+	if len(entry.Resource.CanLinkTo) > 0 {
+		writeWrapped(sb, "Can link to: "+strings.Join(entry.Resource.CanLinkTo, ", "), width, "  ")
+	}
+
+	for _, link := range links {
+		sb.WriteString("\n  ")
+		sb.WriteString(styles.field.Render(link.Type))
+		sb.WriteString("\n")
+		description := link.Description
+		if strings.TrimSpace(description) == "" {
+			description = link.Summary
+		}
+		if strings.TrimSpace(description) != "" {
+			writeWrapped(sb, description, width, "    ")
+		}
+	}
+}
+
+func writeHeading(sb *strings.Builder, heading string, styles *renderStyles) {
+	sb.WriteString("\n")
+	sb.WriteString(styles.heading.Render(strings.ToUpper(heading)))
+	sb.WriteString("\n\n")
+}
+
+// writeWrapped writes the text with each line wrapped at word boundaries
+// to fit within the width, including the indent.
+// Existing line breaks are preserved.
+func writeWrapped(sb *strings.Builder, text string, width int, indent string) {
+	limit := max(width-len(indent), 20)
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			sb.WriteString("\n")
+			continue
+		}
+
+		line := words[0]
+		for _, word := range words[1:] {
+			if len(line)+1+len(word) > limit {
+				sb.WriteString(indent + line + "\n")
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		sb.WriteString(indent + line + "\n")
+	}
+}
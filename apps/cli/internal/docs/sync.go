@@ -0,0 +1,144 @@
+package docs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/plugins"
+)
+
+// Downloader downloads the documentation published with
+// the release of an installed plugin version.
+type Downloader interface {
+	DownloadDocs(ctx context.Context, plugin *plugins.Installed) ([]byte, error)
+}
+
+// SyncStatus describes the outcome of syncing the documentation
+// for an installed plugin.
+type SyncStatus string
+
+const (
+	// SyncStatusIncluded is used when the documentation is included
+	// with the installed plugin so there is nothing to download,
+	// docs downloaded for an earlier version are removed from the cache.
+	SyncStatusIncluded SyncStatus = "included"
+	// SyncStatusDownloaded is used when the documentation has been
+	// downloaded to the cache directory.
+	SyncStatusDownloaded SyncStatus = "downloaded"
+	// SyncStatusNotPublished is used when the plugin release
+	// does not publish documentation.
+	SyncStatusNotPublished SyncStatus = "not published"
+	// SyncStatusFailed is used when the documentation could not be
+	// downloaded or is not in the format of the plugin docs generator.
+	SyncStatusFailed SyncStatus = "failed"
+)
+
+// SyncResult holds the outcome of syncing the documentation
+// for an installed plugin.
+type SyncResult struct {
+	Plugin *plugins.Installed
+	Status SyncStatus
+	// Err is set when the status is SyncStatusFailed.
+	Err error
+}
+
+// Sync downloads the documentation for the latest installed version of each
+// plugin that does not include a docs file to the cache directory so it can
+// be browsed without internet access.
+// The cached docs for a plugin are replaced on each sync so they follow
+// the installed version.
+// A failure for one plugin is recorded in its result and does not stop
+// the documentation for the rest of the plugins from being synced.
+func Sync(
+	ctx context.Context,
+	layout plugins.Layout,
+	cacheDir string,
+	downloader Downloader,
+) ([]*SyncResult, error) {
+	installed, err := layout.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	results := []*SyncResult{}
+	for _, plugin := range latestVersions(installed) {
+		results = append(results, syncPlugin(ctx, layout, cacheDir, downloader, plugin))
+	}
+
+	return results, nil
+}
+
+func syncPlugin(
+	ctx context.Context,
+	layout plugins.Layout,
+	cacheDir string,
+	downloader Downloader,
+	plugin *plugins.Installed,
+) *SyncResult {
+	cachePath := CachePath(cacheDir, plugin.Type, plugin.ID)
+	if _, err := os.Stat(layout.DocsPath(plugin.Type, plugin.ID, plugin.Version)); err == nil {
+		// The docs included with the plugin take precedence over the cache,
+		// cached docs for an earlier version would otherwise be left behind
+		// and never updated.
+		if err := os.Remove(cachePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return &SyncResult{Plugin: plugin, Status: SyncStatusFailed, Err: err}
+		}
+		return &SyncResult{Plugin: plugin, Status: SyncStatusIncluded}
+	}
+
+	content, err := downloader.DownloadDocs(ctx, plugin)
+	if errors.Is(err, plugins.ErrDocsNotPublished) {
+		return &SyncResult{Plugin: plugin, Status: SyncStatusNotPublished}
+	}
+	if err != nil {
+		return &SyncResult{Plugin: plugin, Status: SyncStatusFailed, Err: err}
+	}
+
+	source := fmt.Sprintf("the docs for %s %s", plugin.ID, plugin.Version)
+	if _, err := parsePluginDocs(content, source); err != nil {
+		return &SyncResult{Plugin: plugin, Status: SyncStatusFailed, Err: err}
+	}
+
+	if err := os.WriteFile(cachePath, content, 0o644); err != nil {
+		return &SyncResult{Plugin: plugin, Status: SyncStatusFailed, Err: err}
+	}
+
+	return &SyncResult{Plugin: plugin, Status: SyncStatusDownloaded}
+}
+
+// CachePath determines the path in the cache directory that the
+// downloaded documentation for a plugin is written to,
+// e.g. "provider_registry.bluelink.dev_bluelink_aws.json".
+// The plugin type is included as the same plugin ID can be installed
+// as both a provider and a transformer.
+func CachePath(cacheDir string, pluginType plugins.Type, id plugins.ID) string {
+	name := strings.Join([]string{string(pluginType), id.Host, id.Namespace, id.Name}, "_")
+	return filepath.Join(cacheDir, name+".json")
+}
+
+// latestVersions keeps the newest version of each installed plugin,
+// the installed plugins are ordered from the oldest to the newest version.
+func latestVersions(installed []*plugins.Installed) []*plugins.Installed {
+	latest := []*plugins.Installed{}
+	seen := map[string]bool{}
+	for i := len(installed) - 1; i >= 0; i -= 1 {
+		plugin := installed[i]
+		key := string(plugin.Type) + "/" + plugin.ID.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		latest = append([]*plugins.Installed{plugin}, latest...)
+	}
+
+	return latest
+}
@@ -0,0 +1,246 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// Target is a blueprint instance to check for drift along with
+// the blueprint file the instance was deployed from.
+type Target struct {
+	InstanceID    string
+	BlueprintFile string
+}
+
+// ParseTargets parses a list of instances to check for drift,
+// each entry is either an instance ID, using the default blueprint file,
+// or an instance ID and blueprint file separated by "=",
+// e.g. "instance-1=infra/app.blueprint.yaml".
+func ParseTargets(entries []string, defaultBlueprintFile string) ([]*Target, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("at least one instance ID must be provided")
+	}
+
+	targets := make([]*Target, 0, len(entries))
+	for _, entry := range entries {
+		instanceID, blueprintFile, hasFile := strings.Cut(entry, "=")
+		instanceID = strings.TrimSpace(instanceID)
+		if instanceID == "" {
+			return nil, fmt.Errorf("invalid instance %q, an instance ID must be provided", entry)
+		}
+
+		if !hasFile || strings.TrimSpace(blueprintFile) == "" {
+			blueprintFile = defaultBlueprintFile
+		}
+
+		targets = append(targets, &Target{
+			InstanceID:    instanceID,
+			BlueprintFile: strings.TrimSpace(blueprintFile),
+		})
+	}
+
+	return targets, nil
+}
+
+// Resource holds information about a resource that has drifted
+// from the state recorded in the deploy engine.
+type Resource struct {
+	InstanceID string
+	// Path is the name of the resource in the blueprint, prefixed with the
+	// names of the child blueprints the resource belongs to,
+	// e.g. "coreInfra.ordersTable".
+	Path       string
+	Type       string
+	ResourceID string
+	// DetectedAt holds the time drift was last detected for the resource,
+	// this is the zero value when the deploy engine did not record the time.
+	DetectedAt time.Time
+}
+
+// Key uniquely identifies the drifted resource across all the
+// watched instances.
+func (r *Resource) Key() string {
+	return r.InstanceID + "/" + r.Path
+}
+
+func (r *Resource) String() string {
+	return fmt.Sprintf("~ resource %s (%s)", r.Path, r.Type)
+}
+
+// Report holds the result of checking a blueprint instance for drift.
+type Report struct {
+	InstanceID   string
+	InstanceName string
+	CheckedAt    time.Time
+	// Resources holds the drifted resources in the instance,
+	// ordered by path.
+	Resources []*Resource
+	// Err holds the error that caused the check to fail,
+	// other instances continue to be checked when a check fails.
+	Err error
+}
+
+// Label is the name used to refer to the instance in output,
+// the instance name is preferred over the ID when it is known.
+func (r *Report) Label() string {
+	if r.InstanceName != "" {
+		return r.InstanceName
+	}
+
+	return r.InstanceID
+}
+
+// Check stages changes for the instance to get the deploy engine
+// to check the resources in the instance for drift and then collects
+// the resources that have drifted from the instance state.
+func Check(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	target *Target,
+	config *types.BlueprintOperationConfig,
+	logger *zap.Logger,
+) *Report {
+	report := &Report{InstanceID: target.InstanceID}
+
+	_, err := deploy.StageChanges(
+		ctx,
+		deployEngine,
+		&deploy.Options{
+			BlueprintFile: target.BlueprintFile,
+			InstanceID:    target.InstanceID,
+			Config:        config,
+		},
+		nil,
+		logger,
+	)
+	if err != nil {
+		report.CheckedAt = time.Now()
+		report.Err = err
+		return report
+	}
+
+	instance, err := deployEngine.GetBlueprintInstance(ctx, target.InstanceID)
+	report.CheckedAt = time.Now()
+	if err != nil {
+		report.Err = engine.SimplifyError(err, logger)
+		return report
+	}
+
+	report.InstanceName = instance.InstanceName
+	report.Resources = driftedResources(target.InstanceID, "", instance)
+	slices.SortFunc(report.Resources, func(a, b *Resource) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return report
+}
+
+// CheckAll checks each of the targets for drift in order.
+// Each check stages a change set in the deploy engine that is never deployed,
+// the deploy engine client does not support deleting individual change sets,
+// so the engine's change set clean up is triggered after each round of checks
+// to remove change sets older than the engine's retention period.
+// This keeps the change sets created by long-running watches from accumulating.
+func CheckAll(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	targets []*Target,
+	config *types.BlueprintOperationConfig,
+	logger *zap.Logger,
+) []*Report {
+	reports := make([]*Report, 0, len(targets))
+	for _, target := range targets {
+		reports = append(reports, Check(ctx, deployEngine, target, config, logger))
+	}
+
+	if err := deployEngine.CleanupChangesets(ctx); err != nil {
+		// Failing to clean up change sets does not affect the results
+		// of the drift checks so it is not reported to the user.
+		logger.Warn("failed to clean up change sets after drift checks", zap.Error(err))
+	}
+
+	return reports
+}
+
+func driftedResources(instanceID string, pathPrefix string, instance *state.InstanceState) []*Resource {
+	resources := []*Resource{}
+	for _, resource := range instance.Resources {
+		if !resource.Drifted {
+			continue
+		}
+
+		drifted := &Resource{
+			InstanceID: instanceID,
+			Path:       pathPrefix + resource.Name,
+			Type:       resource.Type,
+			ResourceID: resource.ResourceID,
+		}
+		if resource.LastDriftDetectedTimestamp != nil {
+			drifted.DetectedAt = time.Unix(int64(*resource.LastDriftDetectedTimestamp), 0)
+		}
+		resources = append(resources, drifted)
+	}
+
+	for childName, child := range instance.ChildBlueprints {
+		if child == nil {
+			continue
+		}
+		resources = append(
+			resources,
+			driftedResources(instanceID, pathPrefix+childName+".", child)...,
+		)
+	}
+
+	return resources
+}
+
+// Tracker keeps track of the drifted resources seen in previous checks
+// so that newly detected drift can be told apart from drift that
+// has already been reported.
+type Tracker struct {
+	seen map[string]map[string]bool
+}
+
+// NewTracker creates a new tracker with no previous checks.
+func NewTracker() *Tracker {
+	return &Tracker{
+		seen: map[string]map[string]bool{},
+	}
+}
+
+// Track records the drifted resources in the report and returns the
+// resources that have newly drifted since the previous check of the instance,
+// this includes resources that had no drift in the previous check
+// and have drifted again.
+// The drift detection timestamp is not used, as the deploy engine sets it
+// to the time of the check every time drift is detected for a resource.
+// The first successful check of an instance is used as the baseline,
+// so drift that exists when watching starts is not treated as new.
+// Failed checks are ignored.
+func (t *Tracker) Track(report *Report) []*Resource {
+	if report.Err != nil {
+		return nil
+	}
+
+	previous, hasPrevious := t.seen[report.InstanceID]
+	current := make(map[string]bool, len(report.Resources))
+	newDrift := []*Resource{}
+	for _, resource := range report.Resources {
+		current[resource.Key()] = true
+		if hasPrevious && !previous[resource.Key()] {
+			newDrift = append(newDrift, resource)
+		}
+	}
+	t.seen[report.InstanceID] = current
+
+	return newDrift
+}
@@ -0,0 +1,242 @@
+package drift
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type DriftTestSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func TestDriftTestSuite(t *testing.T) {
+	suite.Run(t, new(DriftTestSuite))
+}
+
+func (s *DriftTestSuite) SetupTest() {
+	logger, _ := zap.NewDevelopment()
+	s.logger = logger
+}
+
+func (s *DriftTestSuite) Test_parses_targets_with_optional_blueprint_files() {
+	targets, err := ParseTargets(
+		[]string{"instance-1", " instance-2 = payments/app.blueprint.yaml"},
+		"app.blueprint.yaml",
+	)
+	s.Require().NoError(err)
+	s.Assert().Equal(
+		[]*Target{
+			{InstanceID: "instance-1", BlueprintFile: "app.blueprint.yaml"},
+			{InstanceID: "instance-2", BlueprintFile: "payments/app.blueprint.yaml"},
+		},
+		targets,
+	)
+
+	_, err = ParseTargets(nil, "app.blueprint.yaml")
+	s.Assert().EqualError(err, "at least one instance ID must be provided")
+
+	_, err = ParseTargets([]string{"=app.blueprint.yaml"}, "app.blueprint.yaml")
+	s.Assert().Error(err)
+}
+
+func (s *DriftTestSuite) Test_collects_drifted_resources_including_child_blueprints() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:       &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn: streamNoChanges,
+		GetBlueprintInstanceResult: &state.InstanceState{
+			InstanceID:   "instance-1",
+			InstanceName: "orders-api",
+			Resources: map[string]*state.ResourceState{
+				"resource-1": driftedResource("ordersTable", "aws/dynamodb/table", 1760000000),
+				"resource-2": {Name: "ordersQueue", Type: "aws/sqs/queue"},
+			},
+			ChildBlueprints: map[string]*state.InstanceState{
+				"coreInfra": {
+					Resources: map[string]*state.ResourceState{
+						"resource-3": driftedResource("vpc", "aws/ec2/vpc", 0),
+					},
+				},
+			},
+		},
+	}
+
+	report := Check(
+		context.Background(),
+		mockEngine,
+		&Target{InstanceID: "instance-1", BlueprintFile: "app.blueprint.yaml"},
+		nil,
+		s.logger,
+	)
+	s.Require().NoError(report.Err)
+	s.Assert().Equal("orders-api", report.Label())
+	s.Require().Len(report.Resources, 2)
+	s.Assert().Equal("coreInfra.vpc", report.Resources[0].Path)
+	s.Assert().True(report.Resources[0].DetectedAt.IsZero())
+	s.Assert().Equal("ordersTable", report.Resources[1].Path)
+	s.Assert().Equal(time.Unix(1760000000, 0), report.Resources[1].DetectedAt)
+	s.Assert().Equal("~ resource ordersTable (aws/dynamodb/table)", report.Resources[1].String())
+}
+
+func (s *DriftTestSuite) Test_records_check_failures_in_report() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetErr: errors.New("engine unavailable"),
+	}
+
+	reports := CheckAll(
+		context.Background(),
+		mockEngine,
+		[]*Target{{InstanceID: "instance-1", BlueprintFile: "app.blueprint.yaml"}},
+		nil,
+		s.logger,
+	)
+	s.Require().Len(reports, 1)
+	s.Assert().Error(reports[0].Err)
+	s.Assert().Equal("instance-1", reports[0].Label())
+	s.Assert().False(reports[0].CheckedAt.IsZero())
+}
+
+func (s *DriftTestSuite) Test_cleans_up_change_sets_after_each_round_of_checks() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetErr:   errors.New("engine unavailable"),
+		CleanupChangesetsErr: errors.New("clean up failed"),
+	}
+	targets := []*Target{
+		{InstanceID: "instance-1", BlueprintFile: "app.blueprint.yaml"},
+		{InstanceID: "instance-2", BlueprintFile: "app.blueprint.yaml"},
+	}
+
+	reports := CheckAll(context.Background(), mockEngine, targets, nil, s.logger)
+	s.Require().Len(reports, 2)
+	s.Assert().Equal(1, mockEngine.CleanupChangesetsCalls)
+
+	CheckAll(context.Background(), mockEngine, targets, nil, s.logger)
+	s.Assert().Equal(2, mockEngine.CleanupChangesetsCalls)
+}
+
+func (s *DriftTestSuite) Test_tracker_reports_new_drift_after_baseline() {
+	tracker := NewTracker()
+	table := &Resource{InstanceID: "instance-1", Path: "ordersTable", DetectedAt: time.Unix(100, 0)}
+
+	s.Assert().Empty(tracker.Track(&Report{InstanceID: "instance-1", Resources: []*Resource{table}}))
+
+	// The deploy engine sets the detection time on every check,
+	// drift that persists between checks is not reported again.
+	tableCheckedAgain := &Resource{InstanceID: "instance-1", Path: "ordersTable", DetectedAt: time.Unix(200, 0)}
+	s.Assert().Empty(tracker.Track(&Report{InstanceID: "instance-1", Resources: []*Resource{tableCheckedAgain}}))
+
+	queue := &Resource{InstanceID: "instance-1", Path: "ordersQueue", DetectedAt: time.Unix(300, 0)}
+	s.Assert().Equal(
+		[]*Resource{queue},
+		tracker.Track(&Report{InstanceID: "instance-1", Resources: []*Resource{tableCheckedAgain, queue}}),
+	)
+
+	s.Assert().Empty(tracker.Track(&Report{InstanceID: "instance-1", Err: errors.New("check failed")}))
+	s.Assert().Empty(tracker.Track(&Report{InstanceID: "instance-1", Resources: []*Resource{queue}}))
+
+	// Drift that went away in the previous check and has come back is new.
+	redetected := &Resource{InstanceID: "instance-1", Path: "ordersTable", DetectedAt: time.Unix(400, 0)}
+	s.Assert().Equal(
+		[]*Resource{redetected},
+		tracker.Track(&Report{InstanceID: "instance-1", Resources: []*Resource{redetected, queue}}),
+	)
+}
+
+func (s *DriftTestSuite) Test_notifies_all_notifiers_of_new_drift() {
+	var bell bytes.Buffer
+	calls := [][]string{}
+	desktop := &desktopNotifier{
+		goos: "linux",
+		run: func(name string, args ...string) error {
+			calls = append(calls, append([]string{name}, args...))
+			return nil
+		},
+	}
+
+	err := NotifyAll(
+		[]Notifier{NewBellNotifier(&bell), desktop},
+		&Report{InstanceID: "instance-1", InstanceName: "orders-api"},
+		[]*Resource{{Path: "ordersTable"}, {Path: "ordersQueue"}},
+	)
+	s.Require().NoError(err)
+	s.Assert().Equal("\a", bell.String())
+	s.Assert().Equal(
+		[][]string{{
+			"notify-send",
+			"--app-name=Celerity",
+			"Drift detected in orders-api",
+			"2 resources have drifted: ordersTable, ordersQueue",
+		}},
+		calls,
+	)
+}
+
+func (s *DriftTestSuite) Test_escapes_desktop_notifications_on_macos() {
+	var script string
+	desktop := &desktopNotifier{
+		goos: "darwin",
+		run: func(_ string, args ...string) error {
+			script = args[1]
+			return nil
+		},
+	}
+
+	s.Require().NoError(desktop.Notify(`Drift in "orders"`, "1 resource has drifted"))
+	s.Assert().Equal(
+		`display notification "1 resource has drifted" with title "Drift in \"orders\""`,
+		script,
+	)
+
+	unsupported := &desktopNotifier{goos: "windows"}
+	s.Assert().ErrorIs(unsupported.Notify("title", "message"), errDesktopNotificationsUnsupported)
+}
+
+func (s *DriftTestSuite) Test_fails_to_parse_unknown_notifier() {
+	notifiers, err := ParseNotifiers([]string{"bell", " desktop"}, &bytes.Buffer{})
+	s.Require().NoError(err)
+	s.Assert().Len(notifiers, 2)
+
+	_, err = ParseNotifiers([]string{"email"}, &bytes.Buffer{})
+	s.Assert().EqualError(err, `unsupported notification method "email", must be one of "bell", "desktop"`)
+}
+
+func driftedResource(name string, resourceType string, detectedAt int) *state.ResourceState {
+	resource := &state.ResourceState{
+		Name:    name,
+		Type:    resourceType,
+		Drifted: true,
+	}
+	if detectedAt > 0 {
+		resource.LastDriftDetectedTimestamp = &detectedAt
+	}
+	return resource
+}
+
+func streamNoChanges(
+	_ context.Context,
+	_ string,
+	streamTo chan<- types.ChangeStagingEvent,
+	_ chan<- error,
+) error {
+	go func() {
+		streamTo <- types.ChangeStagingEvent{
+			ID: "event-1",
+			CompleteChanges: &types.CompleteChangesEventData{
+				Changes: &changes.BlueprintChanges{},
+			},
+		}
+		close(streamTo)
+	}()
+	return nil
+}
@@ -0,0 +1,142 @@
+package drift
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	// NotifyBell rings the terminal bell when new drift is detected.
+	NotifyBell = "bell"
+	// NotifyDesktop shows a desktop notification when new drift is detected.
+	NotifyDesktop = "desktop"
+)
+
+var errDesktopNotificationsUnsupported = errors.New(
+	"desktop notifications are only supported on linux and macos",
+)
+
+// Notifier alerts the user when new drift is detected.
+type Notifier interface {
+	Notify(title string, message string) error
+}
+
+// ParseNotifiers creates the notifiers for the given notification methods,
+// the terminal bell is written to bellWriter.
+func ParseNotifiers(methods []string, bellWriter io.Writer) ([]Notifier, error) {
+	notifiers := []Notifier{}
+	for _, method := range methods {
+		switch strings.TrimSpace(method) {
+		case NotifyBell:
+			notifiers = append(notifiers, NewBellNotifier(bellWriter))
+		case NotifyDesktop:
+			notifiers = append(notifiers, NewDesktopNotifier())
+		default:
+			return nil, fmt.Errorf(
+				"unsupported notification method %q, must be one of %q, %q",
+				method,
+				NotifyBell,
+				NotifyDesktop,
+			)
+		}
+	}
+
+	return notifiers, nil
+}
+
+// NotifyAll sends a notification describing the new drift to all the
+// notifiers, the first error encountered is returned after all notifiers
+// have been tried.
+func NotifyAll(notifiers []Notifier, report *Report, newDrift []*Resource) error {
+	if len(notifiers) == 0 || len(newDrift) == 0 {
+		return nil
+	}
+
+	title, message := notification(report, newDrift)
+	var firstErr error
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(title, message); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func notification(report *Report, newDrift []*Resource) (string, string) {
+	paths := make([]string, len(newDrift))
+	for i, resource := range newDrift {
+		paths[i] = resource.Path
+	}
+
+	noun := "resources have"
+	if len(newDrift) == 1 {
+		noun = "resource has"
+	}
+
+	return fmt.Sprintf("Drift detected in %s", report.Label()),
+		fmt.Sprintf("%d %s drifted: %s", len(newDrift), noun, strings.Join(paths, ", "))
+}
+
+type bellNotifier struct {
+	writer io.Writer
+}
+
+// NewBellNotifier creates a notifier that rings the terminal bell.
+func NewBellNotifier(writer io.Writer) Notifier {
+	return &bellNotifier{writer: writer}
+}
+
+func (n *bellNotifier) Notify(_ string, _ string) error {
+	_, err := io.WriteString(n.writer, "\a")
+	return err
+}
+
+// commandRunner runs an external command.
+type commandRunner func(name string, args ...string) error
+
+func runCommand(name string, args ...string) error {
+	return exec.Command(name, args...).Run()
+}
+
+// desktopNotifier shows desktop notifications through the command
+// line tools that ship with the OS.
+type desktopNotifier struct {
+	run  commandRunner
+	goos string
+}
+
+// NewDesktopNotifier creates a notifier that shows desktop notifications,
+// notify-send is used on linux and osascript is used on macos.
+func NewDesktopNotifier() Notifier {
+	return &desktopNotifier{
+		run:  runCommand,
+		goos: runtime.GOOS,
+	}
+}
+
+func (n *desktopNotifier) Notify(title string, message string) error {
+	switch n.goos {
+	case "darwin":
+		script := fmt.Sprintf(
+			"display notification %s with title %s",
+			appleScriptString(message),
+			appleScriptString(title),
+		)
+		return n.run("osascript", "-e", script)
+	case "linux":
+		return n.run("notify-send", "--app-name=Celerity", title, message)
+	}
+
+	return errDesktopNotificationsUnsupported
+}
+
+func appleScriptString(value string) string {
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+	return `"` + escaped + `"`
+}
@@ -1,17 +1,146 @@
 package engine
 
 import (
-	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
 	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/auth"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
 	"go.uber.org/zap"
+	"golang.org/x/oauth2"
 )
 
+const defaultEndpoint = "http://localhost:8325"
+
+// tokenSourceEndpoint is the token endpoint given to the deploy engine client
+// when the user has logged in, requests to it are answered in process with
+// access tokens from the stored credentials instead of being sent to the
+// provider.
+// A placeholder endpoint is needed as the deploy engine client has no option
+// to provide a token source or an access token, its OAuth2 support only obtains
+// tokens with client credentials from a token endpoint. Answering requests to
+// the placeholder allows the tokens from a device code login to be used
+// and refreshed as well.
+// The ".invalid" top-level domain is reserved so the endpoint can never
+// resolve to a real host.
+const tokenSourceEndpoint = "https://token-source.celerity.invalid/token"
+
 // Create a new deploy engine client based on how the CLI is configured.
 func Create(confProvider *config.Provider, logger *zap.Logger) (DeployEngine, error) {
-	return deployengine.NewClient(
-		deployengine.WithClientAuthMethod(deployengine.AuthMethodAPIKey),
-		deployengine.WithClientEndpoint("http://localhost:8325"),
+	dir, err := auth.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(defaultEndpoint, auth.NewStore(dir), logger)
+}
+
+func newClient(endpoint string, store auth.Store, logger *zap.Logger) (*deployengine.Client, error) {
+	opts := []deployengine.ClientOption{
+		deployengine.WithClientEndpoint(endpoint),
 		deployengine.WithClientConnectProtocol(deployengine.ConnectProtocolTCP),
-		deployengine.WithClientAPIKey("test-api-key"),
+	}
+
+	authOpts, err := authOptions(store, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployengine.NewClient(append(opts, authOpts...)...)
+}
+
+// authOptions uses the credentials stored by "celerity login" when the user
+// has logged in, falling back to an API key otherwise.
+func authOptions(store auth.Store, logger *zap.Logger) ([]deployengine.ClientOption, error) {
+	credentials, err := store.Load()
+	if errors.Is(err, auth.ErrNotLoggedIn) {
+		return []deployengine.ClientOption{
+			deployengine.WithClientAuthMethod(deployengine.AuthMethodAPIKey),
+			deployengine.WithClientAPIKey("test-api-key"),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug(
+		"using stored credentials to authenticate with the deploy engine",
+		zap.String("method", string(credentials.Method)),
 	)
+	tokenSource := auth.NewTokenSource(context.Background(), store, credentials, nil)
+	return []deployengine.ClientOption{
+		deployengine.WithClientAuthMethod(deployengine.AuthMethodOAuth2),
+		deployengine.WithClientOAuth2Config(&deployengine.OAuth2Config{
+			TokenEndpoint: tokenSourceEndpoint,
+			ClientID:      credentials.ClientID,
+		}),
+		deployengine.WithClientHTTPRoundTripper(
+			func(transport *http.Transport) http.RoundTripper {
+				return &tokenSourceTransport{
+					base:        newRetryTransport(transport),
+					tokenSource: tokenSource,
+				}
+			},
+		),
+	}, nil
+}
+
+// tokenSourceTransport answers token requests made by the deploy engine
+// client to the token source endpoint with tokens from the stored
+// credentials, all other requests are sent with the base transport
+// which retries failed requests.
+type tokenSourceTransport struct {
+	base        http.RoundTripper
+	tokenSource oauth2.TokenSource
+}
+
+func (t *tokenSourceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.String() != tokenSourceEndpoint {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	token, err := t.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to obtain an access token from the stored credentials, "+
+				"run \"celerity login\" to log in again: %w",
+			err,
+		)
+	}
+
+	body := map[string]any{
+		"access_token": token.AccessToken,
+		"token_type":   token.Type(),
+	}
+	if !token.Expiry.IsZero() {
+		body["expires_in"] = int(time.Until(token.Expiry).Seconds())
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
 }
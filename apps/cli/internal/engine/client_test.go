@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/auth"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	provider       *httptest.Server
+	engine         *httptest.Server
+	mu             sync.Mutex
+	tokenCount     int
+	grantTypes     []string
+	authorizations []string
+	engineFailures int
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
+
+func (s *ClientTestSuite) SetupTest() {
+	s.tokenCount = 0
+	s.grantTypes = []string{}
+	s.authorizations = []string{}
+	s.engineFailures = 0
+	s.provider = httptest.NewServer(s.providerHandler())
+	s.engine = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+		if s.engineFailures > 0 {
+			s.engineFailures -= 1
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, map[string]any{"id": "instance-1", "name": "orders"})
+	}))
+}
+
+func (s *ClientTestSuite) TearDownTest() {
+	s.provider.Close()
+	s.engine.Close()
+}
+
+func (s *ClientTestSuite) Test_uses_default_login_method_to_authenticate_with_the_deploy_engine() {
+	credentials, err := auth.Login(context.Background(), &auth.LoginOptions{
+		Method:    auth.DefaultMethod,
+		IssuerURL: s.provider.URL,
+		ClientID:  "celerity-cli",
+	}, nil)
+	s.Require().NoError(err)
+
+	store := auth.NewEncryptedFileStore(s.T().TempDir())
+	s.Require().NoError(store.Save(credentials))
+
+	client, err := newClient(s.engine.URL, store, zap.NewNop())
+	s.Require().NoError(err)
+
+	instance, err := client.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Assert().Equal("orders", instance.InstanceName)
+
+	// The token issued on login expires straight away,
+	// so it is refreshed before the request is made.
+	s.Assert().Equal(
+		[]string{"urn:ietf:params:oauth:grant-type:device_code", "refresh_token"},
+		s.grantTypes,
+	)
+	s.Assert().Equal([]string{"Bearer access-token-2"}, s.authorizations)
+
+	stored, err := store.Load()
+	s.Require().NoError(err)
+	s.Assert().Equal("access-token-2", stored.Token.AccessToken)
+}
+
+func (s *ClientTestSuite) Test_retries_failed_requests_when_logged_in() {
+	credentials, err := auth.Login(context.Background(), &auth.LoginOptions{
+		Method:    auth.DefaultMethod,
+		IssuerURL: s.provider.URL,
+		ClientID:  "celerity-cli",
+	}, nil)
+	s.Require().NoError(err)
+
+	store := auth.NewEncryptedFileStore(s.T().TempDir())
+	s.Require().NoError(store.Save(credentials))
+
+	s.engineFailures = 1
+	client, err := newClient(s.engine.URL, store, zap.NewNop())
+	s.Require().NoError(err)
+
+	instance, err := client.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Assert().Equal("orders", instance.InstanceName)
+	s.Assert().Equal([]string{"Bearer access-token-2", "Bearer access-token-2"}, s.authorizations)
+}
+
+func (s *ClientTestSuite) Test_uses_api_key_when_not_logged_in() {
+	client, err := newClient(s.engine.URL, auth.NewEncryptedFileStore(s.T().TempDir()), zap.NewNop())
+	s.Require().NoError(err)
+
+	_, err = client.GetBlueprintInstance(context.Background(), "instance-1")
+	s.Require().NoError(err)
+	s.Assert().Equal([]string{""}, s.authorizations)
+}
+
+// providerHandler serves a minimal OAuth2 provider supporting discovery,
+// device authorization and the token endpoint.
+// Tokens issued for device codes expire immediately.
+func (s *ClientTestSuite) providerHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"token_endpoint":                s.provider.URL + "/token",
+			"device_authorization_endpoint": s.provider.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": s.provider.URL + "/activate",
+			"expires_in":       60,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		grantType := r.FormValue("grant_type")
+		s.grantTypes = append(s.grantTypes, grantType)
+		s.tokenCount += 1
+		expiresIn := 3600
+		if grantType != "refresh_token" {
+			expiresIn = 1
+		}
+		writeJSON(w, map[string]any{
+			"access_token":  fmt.Sprintf("access-token-%d", s.tokenCount),
+			"refresh_token": "refresh-token",
+			"token_type":    "Bearer",
+			"expires_in":    expiresIn,
+		})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
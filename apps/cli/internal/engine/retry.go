@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	retryCount       = 5
+	retryMaxDuration = 30 * time.Second
+)
+
+var retryStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+	http.StatusInternalServerError,
+	http.StatusTooManyRequests,
+}
+
+// retryTransport retries requests that fail with a network error or
+// one of the retryStatusCodes, waiting with exponential backoff and jitter
+// between attempts.
+// This applies the same retry policy as the transport the deploy engine client
+// uses when a round tripper is not provided, which can not be wrapped
+// as it is internal to the deploy engine client module.
+type retryTransport struct {
+	base    http.RoundTripper
+	backoff func(retries int) time.Duration
+}
+
+func newRetryTransport(base http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		base:    base,
+		backoff: backoffWithJitter,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body is read up front so it can be sent again for each retry.
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	for retries := 0; shouldRetry(resp, err) && retries < retryCount; retries += 1 {
+		drainBody(resp)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff(retries)):
+		}
+
+		if req.Body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		resp, err = t.base.RoundTrip(req)
+	}
+
+	return resp, err
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return slices.Contains(retryStatusCodes, resp.StatusCode)
+}
+
+func drainBody(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+func backoffWithJitter(retries int) time.Duration {
+	backoff := math.Min(math.Pow(2, float64(retries)), retryMaxDuration.Seconds())
+	return time.Duration(rand.Float64() * backoff * float64(time.Second))
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+var resourceStatusLabels = map[core.ResourceStatus]string{
+	core.ResourceStatusUnknown:          "unknown",
+	core.ResourceStatusCreating:         "creating",
+	core.ResourceStatusCreated:          "created",
+	core.ResourceStatusCreateFailed:     "create failed",
+	core.ResourceStatusDestroying:       "destroying",
+	core.ResourceStatusDestroyed:        "destroyed",
+	core.ResourceStatusDestroyFailed:    "destroy failed",
+	core.ResourceStatusUpdating:         "updating",
+	core.ResourceStatusUpdated:          "updated",
+	core.ResourceStatusUpdateFailed:     "update failed",
+	core.ResourceStatusRollingBack:      "rolling back",
+	core.ResourceStatusRollbackFailed:   "rollback failed",
+	core.ResourceStatusRollbackComplete: "rollback complete",
+}
+
+// ResourceStatusLabel returns a human-readable label for a resource status
+// reported by the deploy engine.
+func ResourceStatusLabel(status core.ResourceStatus) string {
+	label, ok := resourceStatusLabels[status]
+	if !ok {
+		return resourceStatusLabels[core.ResourceStatusUnknown]
+	}
+
+	return label
+}
+
+var linkStatusLabels = map[core.LinkStatus]string{
+	core.LinkStatusUnknown:                 "unknown",
+	core.LinkStatusCreating:                "creating",
+	core.LinkStatusCreated:                 "created",
+	core.LinkStatusCreateFailed:            "create failed",
+	core.LinkStatusCreateRollingBack:       "rolling back create",
+	core.LinkStatusCreateRollbackFailed:    "create rollback failed",
+	core.LinkStatusCreateRollbackComplete:  "create rollback complete",
+	core.LinkStatusDestroying:              "destroying",
+	core.LinkStatusDestroyed:               "destroyed",
+	core.LinkStatusDestroyFailed:           "destroy failed",
+	core.LinkStatusDestroyRollingBack:      "rolling back destroy",
+	core.LinkStatusDestroyRollbackFailed:   "destroy rollback failed",
+	core.LinkStatusDestroyRollbackComplete: "destroy rollback complete",
+	core.LinkStatusUpdating:                "updating",
+	core.LinkStatusUpdated:                 "updated",
+	core.LinkStatusUpdateFailed:            "update failed",
+	core.LinkStatusUpdateRollingBack:       "rolling back update",
+	core.LinkStatusUpdateRollbackFailed:    "update rollback failed",
+	core.LinkStatusUpdateRollbackComplete:  "update rollback complete",
+}
+
+// LinkStatusLabel returns a human-readable label for a link status
+// reported by the deploy engine.
+func LinkStatusLabel(status core.LinkStatus) string {
+	label, ok := linkStatusLabels[status]
+	if !ok {
+		return linkStatusLabels[core.LinkStatusUnknown]
+	}
+
+	return label
+}
+
+var instanceStatusLabels = map[core.InstanceStatus]string{
+	core.InstanceStatusPreparing:               "preparing",
+	core.InstanceStatusDeploying:               "deploying",
+	core.InstanceStatusDeployed:                "deployed",
+	core.InstanceStatusDeployFailed:            "deploy failed",
+	core.InstanceStatusDeployRollingBack:       "rolling back deployment",
+	core.InstanceStatusDeployRollbackFailed:    "deployment rollback failed",
+	core.InstanceStatusDeployRollbackComplete:  "deployment rolled back",
+	core.InstanceStatusDestroying:              "destroying",
+	core.InstanceStatusDestroyed:               "destroyed",
+	core.InstanceStatusDestroyFailed:           "destroy failed",
+	core.InstanceStatusDestroyRollingBack:      "rolling back destroy",
+	core.InstanceStatusDestroyRollbackFailed:   "destroy rollback failed",
+	core.InstanceStatusDestroyRollbackComplete: "destroy rolled back",
+	core.InstanceStatusUpdating:                "updating",
+	core.InstanceStatusUpdated:                 "updated",
+	core.InstanceStatusUpdateFailed:            "update failed",
+	core.InstanceStatusUpdateRollingBack:       "rolling back update",
+	core.InstanceStatusUpdateRollbackFailed:    "update rollback failed",
+	core.InstanceStatusUpdateRollbackComplete:  "update rolled back",
+	core.InstanceStatusNotDeployed:             "not deployed",
+}
+
+// InstanceStatusLabel returns a human-readable label for the status
+// of a blueprint instance or child blueprint reported by the deploy engine.
+func InstanceStatusLabel(status core.InstanceStatus) string {
+	label, ok := instanceStatusLabels[status]
+	if !ok {
+		return "unknown"
+	}
+
+	return label
+}
+
+// IsInstanceStatusInProgress determines whether the given status
+// represents a deployment, update, destroy or rollback that is still in progress.
+func IsInstanceStatusInProgress(status core.InstanceStatus) bool {
+	return status == core.InstanceStatusPreparing ||
+		status == core.InstanceStatusDeploying ||
+		status == core.InstanceStatusUpdating ||
+		status == core.InstanceStatusDestroying ||
+		IsInstanceStatusRollingBack(status)
+}
+
+// IsInstanceStatusRollingBack determines whether the given status
+// represents a rollback that is in progress.
+func IsInstanceStatusRollingBack(status core.InstanceStatus) bool {
+	return status == core.InstanceStatusDeployRollingBack ||
+		status == core.InstanceStatusUpdateRollingBack ||
+		status == core.InstanceStatusDestroyRollingBack
+}
+
+// IsInstanceStatusFailure determines whether the given status
+// represents a failed operation, this includes failed rollbacks.
+func IsInstanceStatusFailure(status core.InstanceStatus) bool {
+	return status == core.InstanceStatusDeployFailed ||
+		status == core.InstanceStatusUpdateFailed ||
+		status == core.InstanceStatusDestroyFailed ||
+		status == core.InstanceStatusDeployRollbackFailed ||
+		status == core.InstanceStatusUpdateRollbackFailed ||
+		status == core.InstanceStatusDestroyRollbackFailed
+}
+
+// IsInstanceStatusSuccess determines whether the given status
+// represents a successfully completed deployment, update or destroy operation.
+func IsInstanceStatusSuccess(status core.InstanceStatus) bool {
+	return status == core.InstanceStatusDeployed ||
+		status == core.InstanceStatusUpdated ||
+		status == core.InstanceStatusDestroyed
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FilterOptions determines which nodes of a graph are kept
+// when filtering a graph.
+type FilterOptions struct {
+	// ElementTypes restricts the graph to nodes of the given element types,
+	// when empty, nodes of all element types are kept.
+	ElementTypes []ElementType
+	// Focus is the ID of a node (e.g. "resources.ordersTable") to restrict
+	// the graph to, only the focused node, the elements it depends on
+	// and the elements that depend on it (directly or transitively) are kept.
+	// When empty, the whole graph is kept.
+	Focus string
+}
+
+// Filter produces a new graph with the nodes that match the given options,
+// only edges between nodes that are kept are included in the filtered graph.
+// The subgraph for the focused node is determined before filtering by element type
+// so dependencies through elements of other types are still followed.
+func Filter(graph *Graph, opts FilterOptions) (*Graph, error) {
+	keep := map[string]bool{}
+	for _, node := range graph.Nodes {
+		keep[node.ID] = true
+	}
+
+	if opts.Focus != "" {
+		if graph.Node(opts.Focus) == nil {
+			return nil, fmt.Errorf(
+				"element %q to focus on was not found in the blueprint, "+
+					"elements must be in the form <type>.<name>, e.g. resources.ordersTable",
+				opts.Focus,
+			)
+		}
+		keep = subgraph(graph, opts.Focus)
+	}
+
+	filtered := &Graph{
+		Nodes: []*Node{},
+		Edges: []*Edge{},
+	}
+	for _, node := range graph.Nodes {
+		if keep[node.ID] && matchesElementTypes(node, opts.ElementTypes) {
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		if filtered.Node(edge.From) != nil && filtered.Node(edge.To) != nil {
+			filtered.Edges = append(filtered.Edges, edge)
+		}
+	}
+
+	return filtered, nil
+}
+
+// ParseElementTypes parses a list of element type names
+// as provided in command line flags.
+func ParseElementTypes(values []string) ([]ElementType, error) {
+	elementTypes := []ElementType{}
+	for _, value := range values {
+		elementType := ElementType(strings.TrimSpace(value))
+		if !slices.Contains(ElementTypes, elementType) {
+			return nil, fmt.Errorf(
+				"unsupported element type %q, expected one of: %s",
+				value,
+				strings.Join(elementTypeNames(), ", "),
+			)
+		}
+		elementTypes = append(elementTypes, elementType)
+	}
+
+	return elementTypes, nil
+}
+
+func matchesElementTypes(node *Node, elementTypes []ElementType) bool {
+	return len(elementTypes) == 0 || slices.Contains(elementTypes, node.ElementType)
+}
+
+func subgraph(graph *Graph, focus string) map[string]bool {
+	dependencies := map[string][]string{}
+	dependents := map[string][]string{}
+	for _, edge := range graph.Edges {
+		dependencies[edge.From] = append(dependencies[edge.From], edge.To)
+		dependents[edge.To] = append(dependents[edge.To], edge.From)
+	}
+
+	keep := map[string]bool{focus: true}
+	collectReachable(focus, dependencies, keep)
+	collectReachable(focus, dependents, keep)
+	return keep
+}
+
+func collectReachable(from string, adjacent map[string][]string, reached map[string]bool) {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacent[current] {
+			if !visited[next] {
+				visited[next] = true
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+func elementTypeNames() []string {
+	names := make([]string, len(ElementTypes))
+	for i, elementType := range ElementTypes {
+		names[i] = string(elementType)
+	}
+	return names
+}
@@ -0,0 +1,489 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/links"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/substitutions"
+	cliblueprint "github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+)
+
+// ElementType is the type of blueprint element that a node
+// in the graph represents.
+type ElementType string
+
+const (
+	// ElementTypeVariable is a blueprint variable.
+	ElementTypeVariable ElementType = "variable"
+	// ElementTypeValue is a blueprint value.
+	ElementTypeValue ElementType = "value"
+	// ElementTypeDataSource is a blueprint data source.
+	ElementTypeDataSource ElementType = "datasource"
+	// ElementTypeResource is a blueprint resource.
+	ElementTypeResource ElementType = "resource"
+	// ElementTypeChild is a child blueprint included in a blueprint.
+	ElementTypeChild ElementType = "child"
+	// ElementTypeExport is a blueprint export.
+	ElementTypeExport ElementType = "export"
+)
+
+// ElementTypes holds all the element types that can be
+// included in a graph in the order they are rendered.
+var ElementTypes = []ElementType{
+	ElementTypeVariable,
+	ElementTypeValue,
+	ElementTypeDataSource,
+	ElementTypeResource,
+	ElementTypeChild,
+	ElementTypeExport,
+}
+
+// EdgeKind describes the relationship between two nodes in the graph.
+type EdgeKind string
+
+const (
+	// EdgeKindReference is used when an element references another
+	// element in a `${..}` substitution.
+	EdgeKindReference EdgeKind = "reference"
+	// EdgeKindDependsOn is used when a resource explicitly depends
+	// on another resource with the `dependsOn` field.
+	EdgeKindDependsOn EdgeKind = "dependsOn"
+	// EdgeKindLink is used when a resource selects another resource
+	// for a link with a `linkSelector`.
+	EdgeKindLink EdgeKind = "link"
+)
+
+// Node represents an element of a blueprint in the graph.
+type Node struct {
+	// ID is the unique identifier of the node in the form
+	// used to reference the element in a substitution,
+	// e.g. "resources.ordersTable".
+	ID string `json:"id"`
+	// Name is the name of the element in the blueprint.
+	Name string `json:"name"`
+	// ElementType is the type of blueprint element
+	// that the node represents.
+	ElementType ElementType `json:"elementType"`
+	// Type is the type of the element such as the resource type
+	// for resources or the data type for values and variables.
+	Type string `json:"type,omitempty"`
+}
+
+// Edge represents a dependency between two nodes in the graph,
+// the element represented by From depends on the element represented by To.
+// For links, From is the resource that selects To with a link selector.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+// Graph holds the elements of a blueprint and the dependencies between them.
+// Nodes and edges are sorted to produce stable output.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+// Node retrieves the node with the given ID, returning nil
+// if the graph does not contain the node.
+func (g *Graph) Node(id string) *Node {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// Build creates a dependency graph from the given blueprint.
+// Dependencies are derived from substitutions in element definitions,
+// explicit `dependsOn` declarations and link selectors.
+// References to elements that are not defined in the blueprint are ignored,
+// validation is responsible for reporting these.
+func Build(blueprint *schema.Blueprint) *Graph {
+	builder := &graphBuilder{
+		nodes: map[string]*Node{},
+		edges: map[edgeKey]*Edge{},
+	}
+
+	builder.addNodes(blueprint)
+	builder.addReferenceEdges(blueprint)
+	builder.addDependsOnEdges(blueprint)
+	builder.addLinkEdges(blueprint)
+
+	return builder.graph()
+}
+
+type edgeKey struct {
+	from string
+	to   string
+	kind EdgeKind
+}
+
+type graphBuilder struct {
+	nodes map[string]*Node
+	edges map[edgeKey]*Edge
+}
+
+func (b *graphBuilder) addNodes(blueprint *schema.Blueprint) {
+	if blueprint.Variables != nil {
+		for name, variable := range blueprint.Variables.Values {
+			varType := ""
+			if variable.Type != nil {
+				varType = string(variable.Type.Value)
+			}
+			b.addNode(ElementTypeVariable, "variables", name, varType)
+		}
+	}
+
+	if blueprint.Values != nil {
+		for name, value := range blueprint.Values.Values {
+			valueType := ""
+			if value.Type != nil {
+				valueType = string(value.Type.Value)
+			}
+			b.addNode(ElementTypeValue, "values", name, valueType)
+		}
+	}
+
+	if blueprint.DataSources != nil {
+		for name, dataSource := range blueprint.DataSources.Values {
+			dataSourceType := ""
+			if dataSource.Type != nil {
+				dataSourceType = dataSource.Type.Value
+			}
+			b.addNode(ElementTypeDataSource, "datasources", name, dataSourceType)
+		}
+	}
+
+	if blueprint.Resources != nil {
+		for name, resource := range blueprint.Resources.Values {
+			resourceType := ""
+			if resource.Type != nil {
+				resourceType = resource.Type.Value
+			}
+			b.addNode(ElementTypeResource, "resources", name, resourceType)
+		}
+	}
+
+	if blueprint.Include != nil {
+		for name := range blueprint.Include.Values {
+			b.addNode(ElementTypeChild, "children", name, "")
+		}
+	}
+
+	if blueprint.Exports != nil {
+		for name, export := range blueprint.Exports.Values {
+			exportType := ""
+			if export.Type != nil {
+				exportType = string(export.Type.Value)
+			}
+			b.addNode(ElementTypeExport, "exports", name, exportType)
+		}
+	}
+}
+
+func (b *graphBuilder) addNode(elementType ElementType, prefix string, name string, nodeType string) {
+	id := fmt.Sprintf("%s.%s", prefix, name)
+	b.nodes[id] = &Node{
+		ID:          id,
+		Name:        name,
+		ElementType: elementType,
+		Type:        nodeType,
+	}
+}
+
+func (b *graphBuilder) addEdge(from string, to string, kind EdgeKind) {
+	if from == to {
+		return
+	}
+
+	if _, hasFrom := b.nodes[from]; !hasFrom {
+		return
+	}
+
+	if _, hasTo := b.nodes[to]; !hasTo {
+		return
+	}
+
+	key := edgeKey{from: from, to: to, kind: kind}
+	b.edges[key] = &Edge{From: from, To: to, Kind: kind}
+}
+
+func (b *graphBuilder) addReferenceEdges(blueprint *schema.Blueprint) {
+	if blueprint.Values != nil {
+		for name, value := range blueprint.Values.Values {
+			refs := &referenceCollector{}
+			refs.collectMappingNode(value.Value)
+			refs.collectStringOrSubs(value.Description)
+			b.addReferences(fmt.Sprintf("values.%s", name), refs)
+		}
+	}
+
+	if blueprint.DataSources != nil {
+		for name, dataSource := range blueprint.DataSources.Values {
+			refs := &referenceCollector{}
+			refs.collectDataSource(dataSource)
+			b.addReferences(fmt.Sprintf("datasources.%s", name), refs)
+		}
+	}
+
+	if blueprint.Resources != nil {
+		for name, resource := range blueprint.Resources.Values {
+			refs := &referenceCollector{}
+			refs.collectResource(resource)
+			b.addReferences(fmt.Sprintf("resources.%s", name), refs)
+		}
+	}
+
+	if blueprint.Include != nil {
+		for name, include := range blueprint.Include.Values {
+			refs := &referenceCollector{}
+			refs.collectStringOrSubs(include.Path)
+			refs.collectMappingNode(include.Variables)
+			refs.collectMappingNode(include.Metadata)
+			refs.collectStringOrSubs(include.Description)
+			b.addReferences(fmt.Sprintf("children.%s", name), refs)
+		}
+	}
+
+	if blueprint.Exports != nil {
+		for name, export := range blueprint.Exports.Values {
+			refs := &referenceCollector{}
+			refs.collectExportField(export.Field)
+			refs.collectStringOrSubs(export.Description)
+			b.addReferences(fmt.Sprintf("exports.%s", name), refs)
+		}
+	}
+}
+
+func (b *graphBuilder) addReferences(from string, refs *referenceCollector) {
+	for _, ref := range refs.refs {
+		b.addEdge(from, ref, EdgeKindReference)
+	}
+}
+
+func (b *graphBuilder) addDependsOnEdges(blueprint *schema.Blueprint) {
+	if blueprint.Resources == nil {
+		return
+	}
+
+	for name, resource := range blueprint.Resources.Values {
+		if resource.DependsOn == nil {
+			continue
+		}
+
+		for _, dependency := range resource.DependsOn.Values {
+			b.addEdge(
+				fmt.Sprintf("resources.%s", name),
+				fmt.Sprintf("resources.%s", dependency),
+				EdgeKindDependsOn,
+			)
+		}
+	}
+}
+
+func (b *graphBuilder) addLinkEdges(blueprint *schema.Blueprint) {
+	if blueprint.Resources == nil {
+		return
+	}
+
+	groups := links.GroupResourcesBySelector(cliblueprint.NewSpec(blueprint))
+	for _, group := range groups {
+		for _, selector := range group.SelectorResources {
+			for _, candidate := range group.CandidateResourcesForSelection {
+				b.addEdge(
+					fmt.Sprintf("resources.%s", selector.Name),
+					fmt.Sprintf("resources.%s", candidate.Name),
+					EdgeKindLink,
+				)
+			}
+		}
+	}
+}
+
+func (b *graphBuilder) graph() *Graph {
+	graph := &Graph{
+		Nodes: make([]*Node, 0, len(b.nodes)),
+		Edges: make([]*Edge, 0, len(b.edges)),
+	}
+
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, edge := range b.edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+
+	sortGraph(graph)
+	return graph
+}
+
+func sortGraph(graph *Graph) {
+	slices.SortFunc(graph.Nodes, func(a, b *Node) int {
+		if a.ElementType != b.ElementType {
+			return slices.Index(ElementTypes, a.ElementType) - slices.Index(ElementTypes, b.ElementType)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	slices.SortFunc(graph.Edges, func(a, b *Edge) int {
+		if a.From != b.From {
+			return strings.Compare(a.From, b.From)
+		}
+		if a.To != b.To {
+			return strings.Compare(a.To, b.To)
+		}
+		return strings.Compare(string(a.Kind), string(b.Kind))
+	})
+}
+
+// referenceCollector collects the IDs of the elements
+// referenced in substitutions of an element definition.
+type referenceCollector struct {
+	refs []string
+}
+
+func (c *referenceCollector) collectResource(resource *schema.Resource) {
+	c.collectStringOrSubs(resource.Description)
+	c.collectStringOrSubs(resource.Each)
+	c.collectCondition(resource.Condition)
+	c.collectMappingNode(resource.Spec)
+
+	if resource.Metadata != nil {
+		c.collectStringOrSubs(resource.Metadata.DisplayName)
+		c.collectStringOrSubsMap(resource.Metadata.Annotations)
+		c.collectMappingNode(resource.Metadata.Custom)
+	}
+}
+
+func (c *referenceCollector) collectDataSource(dataSource *schema.DataSource) {
+	c.collectStringOrSubs(dataSource.Description)
+
+	if dataSource.DataSourceMetadata != nil {
+		c.collectStringOrSubs(dataSource.DataSourceMetadata.DisplayName)
+		c.collectStringOrSubsMap(dataSource.DataSourceMetadata.Annotations)
+		c.collectMappingNode(dataSource.DataSourceMetadata.Custom)
+	}
+
+	if dataSource.Filter == nil {
+		return
+	}
+
+	for _, filter := range dataSource.Filter.Filters {
+		if filter == nil || filter.Search == nil {
+			continue
+		}
+
+		for _, search := range filter.Search.Values {
+			c.collectStringOrSubs(search)
+		}
+	}
+}
+
+func (c *referenceCollector) collectCondition(condition *schema.Condition) {
+	if condition == nil {
+		return
+	}
+
+	for _, and := range condition.And {
+		c.collectCondition(and)
+	}
+
+	for _, or := range condition.Or {
+		c.collectCondition(or)
+	}
+
+	c.collectCondition(condition.Not)
+	c.collectStringOrSubs(condition.StringValue)
+}
+
+// The field of an export is a reference to an element property
+// without the "${..}" wrapper, e.g. "resources.ordersTable.spec.id".
+func (c *referenceCollector) collectExportField(field *bpcore.ScalarValue) {
+	fieldRef := bpcore.StringValueFromScalar(field)
+	if fieldRef == "" {
+		return
+	}
+
+	sub, err := substitutions.ParseSubstitution("", fieldRef, nil, false, true)
+	if err != nil {
+		return
+	}
+
+	c.collectSubstitution(sub)
+}
+
+func (c *referenceCollector) collectStringOrSubsMap(values *schema.StringOrSubstitutionsMap) {
+	if values == nil {
+		return
+	}
+
+	for _, value := range values.Values {
+		c.collectStringOrSubs(value)
+	}
+}
+
+func (c *referenceCollector) collectMappingNode(node *bpcore.MappingNode) {
+	if node == nil {
+		return
+	}
+
+	c.collectStringOrSubs(node.StringWithSubstitutions)
+
+	for _, field := range node.Fields {
+		c.collectMappingNode(field)
+	}
+
+	for _, item := range node.Items {
+		c.collectMappingNode(item)
+	}
+}
+
+func (c *referenceCollector) collectStringOrSubs(value *substitutions.StringOrSubstitutions) {
+	if value == nil {
+		return
+	}
+
+	for _, part := range value.Values {
+		if part != nil {
+			c.collectSubstitution(part.SubstitutionValue)
+		}
+	}
+}
+
+func (c *referenceCollector) collectSubstitution(sub *substitutions.Substitution) {
+	if sub == nil {
+		return
+	}
+
+	switch {
+	case sub.Variable != nil:
+		c.add("variables", sub.Variable.VariableName)
+	case sub.ValueReference != nil:
+		c.add("values", sub.ValueReference.ValueName)
+	case sub.DataSourceProperty != nil:
+		c.add("datasources", sub.DataSourceProperty.DataSourceName)
+	case sub.ResourceProperty != nil:
+		c.add("resources", sub.ResourceProperty.ResourceName)
+	case sub.Child != nil:
+		c.add("children", sub.Child.ChildName)
+	case sub.Function != nil:
+		for _, arg := range sub.Function.Arguments {
+			if arg != nil {
+				c.collectSubstitution(arg.Value)
+			}
+		}
+	}
+}
+
+func (c *referenceCollector) add(prefix string, name string) {
+	c.refs = append(c.refs, fmt.Sprintf("%s.%s", prefix, name))
+}
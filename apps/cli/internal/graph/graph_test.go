@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+	"github.com/stretchr/testify/suite"
+)
+
+const testBlueprint = `
+version: 2025-11-02
+variables:
+  environment:
+    type: string
+  region:
+    type: string
+values:
+  tableName:
+    type: string
+    value: "orders-${variables.environment}"
+datasources:
+  network:
+    type: aws/vpc
+    filter:
+      field: region
+      operator: "="
+      search: "${variables.region}"
+    exports:
+      vpcId:
+        type: string
+include:
+  shared:
+    path: shared.blueprint.yaml
+    variables:
+      region: "${variables.region}"
+resources:
+  ordersTable:
+    type: aws/dynamodb/table
+    metadata:
+      labels:
+        app: orders
+    spec:
+      tableName: "${values.tableName}"
+      vpcId: "${datasources.network.vpcId}"
+  ordersHandler:
+    type: aws/lambda/function
+    dependsOn: [ordersQueue]
+    linkSelector:
+      byLabel:
+        app: orders
+    spec:
+      environment:
+        TABLE_NAME: "${trim(resources.ordersTable.spec.tableName)}"
+        SHARED_ID: "${children.shared.id}"
+  ordersQueue:
+    type: aws/sqs/queue
+    spec:
+      queueName: orders
+exports:
+  tableArn:
+    type: string
+    field: resources.ordersTable.spec.arn
+`
+
+type GraphTestSuite struct {
+	suite.Suite
+	graph *Graph
+}
+
+func TestGraphTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphTestSuite))
+}
+
+func (s *GraphTestSuite) SetupTest() {
+	bp, err := schema.LoadString(testBlueprint, schema.YAMLSpecFormat)
+	s.Require().NoError(err)
+	s.graph = Build(bp)
+}
+
+func (s *GraphTestSuite) Test_builds_nodes_for_all_elements_in_order() {
+	ids := []string{}
+	for _, node := range s.graph.Nodes {
+		ids = append(ids, node.ID)
+	}
+
+	s.Assert().Equal([]string{
+		"variables.environment",
+		"variables.region",
+		"values.tableName",
+		"datasources.network",
+		"resources.ordersHandler",
+		"resources.ordersQueue",
+		"resources.ordersTable",
+		"children.shared",
+		"exports.tableArn",
+	}, ids)
+
+	node := s.graph.Node("resources.ordersTable")
+	s.Require().NotNil(node)
+	s.Assert().Equal(ElementTypeResource, node.ElementType)
+	s.Assert().Equal("aws/dynamodb/table", node.Type)
+}
+
+func (s *GraphTestSuite) Test_builds_edges_from_references_dependencies_and_links() {
+	s.Assert().Equal([]*Edge{
+		{From: "children.shared", To: "variables.region", Kind: EdgeKindReference},
+		{From: "datasources.network", To: "variables.region", Kind: EdgeKindReference},
+		{From: "exports.tableArn", To: "resources.ordersTable", Kind: EdgeKindReference},
+		{From: "resources.ordersHandler", To: "children.shared", Kind: EdgeKindReference},
+		{From: "resources.ordersHandler", To: "resources.ordersQueue", Kind: EdgeKindDependsOn},
+		{From: "resources.ordersHandler", To: "resources.ordersTable", Kind: EdgeKindLink},
+		{From: "resources.ordersHandler", To: "resources.ordersTable", Kind: EdgeKindReference},
+		{From: "resources.ordersTable", To: "datasources.network", Kind: EdgeKindReference},
+		{From: "resources.ordersTable", To: "values.tableName", Kind: EdgeKindReference},
+		{From: "values.tableName", To: "variables.environment", Kind: EdgeKindReference},
+	}, s.graph.Edges)
+}
+
+func (s *GraphTestSuite) Test_filters_by_element_type() {
+	filtered, err := Filter(s.graph, FilterOptions{
+		ElementTypes: []ElementType{ElementTypeResource},
+	})
+	s.Require().NoError(err)
+	s.Assert().Len(filtered.Nodes, 3)
+	s.Assert().Equal([]*Edge{
+		{From: "resources.ordersHandler", To: "resources.ordersQueue", Kind: EdgeKindDependsOn},
+		{From: "resources.ordersHandler", To: "resources.ordersTable", Kind: EdgeKindLink},
+		{From: "resources.ordersHandler", To: "resources.ordersTable", Kind: EdgeKindReference},
+	}, filtered.Edges)
+}
+
+func (s *GraphTestSuite) Test_filters_to_subgraph_of_focused_element() {
+	filtered, err := Filter(s.graph, FilterOptions{
+		Focus: "values.tableName",
+	})
+	s.Require().NoError(err)
+
+	ids := []string{}
+	for _, node := range filtered.Nodes {
+		ids = append(ids, node.ID)
+	}
+	s.Assert().Equal([]string{
+		"variables.environment",
+		"values.tableName",
+		"resources.ordersHandler",
+		"resources.ordersTable",
+		"exports.tableArn",
+	}, ids)
+}
+
+func (s *GraphTestSuite) Test_returns_error_for_missing_focus_element() {
+	_, err := Filter(s.graph, FilterOptions{
+		Focus: "resources.missing",
+	})
+	s.Assert().ErrorContains(err, `element "resources.missing" to focus on was not found`)
+}
+
+func (s *GraphTestSuite) Test_parses_element_types() {
+	elementTypes, err := ParseElementTypes([]string{"resource", " datasource"})
+	s.Require().NoError(err)
+	s.Assert().Equal([]ElementType{ElementTypeResource, ElementTypeDataSource}, elementTypes)
+
+	_, err = ParseElementTypes([]string{"resources"})
+	s.Assert().ErrorContains(err, `unsupported element type "resources"`)
+}
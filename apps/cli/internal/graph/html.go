@@ -0,0 +1,93 @@
+package graph
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+)
+
+//go:embed viewer.html
+var viewerTemplateSource string
+
+var viewerTemplate = template.Must(template.New("viewer").Parse(viewerTemplateSource))
+
+type viewerData struct {
+	Title        string
+	Graph        *Graph
+	Direction    Direction
+	Directions   []Direction
+	ElementTypes []ElementType
+	NodeStyles   map[ElementType]viewerNodeStyle
+	EdgeStyles   map[EdgeKind]viewerEdgeStyle
+}
+
+// viewerNodeStyle determines how the viewer draws the nodes
+// for an element type.
+type viewerNodeStyle struct {
+	// Shape is one of "pill", "parallelogram", "cylinder", "rect",
+	// "double" or "flag", matching the shapes used in Mermaid flowcharts.
+	Shape  string `json:"shape"`
+	Fill   string `json:"fill"`
+	Stroke string `json:"stroke"`
+}
+
+// viewerEdgeStyle determines how the viewer draws the edges
+// for a kind of dependency.
+type viewerEdgeStyle struct {
+	Width  float64 `json:"width"`
+	Dashed bool    `json:"dashed"`
+	Label  string  `json:"label,omitempty"`
+}
+
+var viewerNodeShapes = map[ElementType]string{
+	ElementTypeVariable:   "pill",
+	ElementTypeValue:      "parallelogram",
+	ElementTypeDataSource: "cylinder",
+	ElementTypeResource:   "rect",
+	ElementTypeChild:      "double",
+	ElementTypeExport:     "flag",
+}
+
+var viewerEdgeStyles = map[EdgeKind]viewerEdgeStyle{
+	EdgeKindReference: {Width: 1.5},
+	EdgeKindDependsOn: {Width: 3},
+	EdgeKindLink:      {Width: 1.5, Dashed: true, Label: "link"},
+}
+
+// RenderHTML writes the graph as a self-contained HTML page
+// with an interactive viewer.
+// The viewer allows element types to be toggled, the layout direction
+// to be changed and the graph to be focused on the subgraph of a clicked element.
+// The graph is laid out and drawn as SVG by a script embedded in the page,
+// no scripts or styles are loaded from other locations so the page
+// can be viewed offline.
+func RenderHTML(w io.Writer, graph *Graph, opts RenderOptions) error {
+	title := opts.Title
+	if title == "" {
+		title = "Blueprint graph"
+	}
+
+	return viewerTemplate.Execute(w, viewerData{
+		Title:        title,
+		Graph:        graph,
+		Direction:    directionOrDefault(opts.Direction),
+		Directions:   Directions,
+		ElementTypes: ElementTypes,
+		NodeStyles:   viewerNodeStyles(),
+		EdgeStyles:   viewerEdgeStyles,
+	})
+}
+
+func viewerNodeStyles() map[ElementType]viewerNodeStyle {
+	styles := make(map[ElementType]viewerNodeStyle, len(ElementTypes))
+	for _, elementType := range ElementTypes {
+		colours := elementColours[elementType]
+		styles[elementType] = viewerNodeStyle{
+			Shape:  viewerNodeShapes[elementType],
+			Fill:   colours.fill,
+			Stroke: colours.stroke,
+		}
+	}
+
+	return styles
+}
@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Format is an output format that a graph can be rendered in.
+type Format string
+
+const (
+	// FormatDOT renders a graph in the Graphviz DOT language.
+	FormatDOT Format = "dot"
+	// FormatMermaid renders a graph as a Mermaid flowchart.
+	FormatMermaid Format = "mermaid"
+	// FormatHTML renders a graph as a self-contained HTML page
+	// with an interactive viewer.
+	FormatHTML Format = "html"
+)
+
+// Formats holds all the supported output formats.
+var Formats = []Format{FormatDOT, FormatMermaid, FormatHTML}
+
+// Direction is the direction that the graph is laid out in.
+type Direction string
+
+const (
+	// DirectionLeftRight lays out the graph from left to right.
+	DirectionLeftRight Direction = "LR"
+	// DirectionRightLeft lays out the graph from right to left.
+	DirectionRightLeft Direction = "RL"
+	// DirectionTopBottom lays out the graph from top to bottom.
+	DirectionTopBottom Direction = "TB"
+	// DirectionBottomTop lays out the graph from bottom to top.
+	DirectionBottomTop Direction = "BT"
+)
+
+// Directions holds all the supported layout directions.
+var Directions = []Direction{
+	DirectionLeftRight,
+	DirectionRightLeft,
+	DirectionTopBottom,
+	DirectionBottomTop,
+}
+
+// RenderOptions provides options that control how a graph is rendered.
+type RenderOptions struct {
+	// Direction is the direction that the graph is laid out in,
+	// defaults to left to right.
+	Direction Direction
+	// Title is displayed in the HTML viewer,
+	// this is ignored for other formats.
+	Title string
+}
+
+// ParseFormat parses an output format as provided in command line flags.
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+	if !slices.Contains(Formats, format) {
+		return "", fmt.Errorf("unsupported graph format %q, expected one of: dot, mermaid, html", value)
+	}
+
+	return format, nil
+}
+
+// ParseDirection parses a layout direction as provided in command line flags.
+func ParseDirection(value string) (Direction, error) {
+	direction := Direction(strings.ToUpper(strings.TrimSpace(value)))
+	if !slices.Contains(Directions, direction) {
+		return "", fmt.Errorf("unsupported graph direction %q, expected one of: LR, RL, TB, BT", value)
+	}
+
+	return direction, nil
+}
+
+// Render writes the graph to the given writer in the given format.
+func Render(w io.Writer, graph *Graph, format Format, opts RenderOptions) error {
+	if opts.Direction == "" {
+		opts.Direction = DirectionLeftRight
+	}
+
+	switch format {
+	case FormatDOT:
+		return RenderDOT(w, graph, opts)
+	case FormatMermaid:
+		return RenderMermaid(w, graph, opts)
+	case FormatHTML:
+		return RenderHTML(w, graph, opts)
+	default:
+		return fmt.Errorf("unsupported graph format %q", format)
+	}
+}
+
+var dotNodeShapes = map[ElementType]string{
+	ElementTypeVariable:   "ellipse",
+	ElementTypeValue:      "note",
+	ElementTypeDataSource: "cylinder",
+	ElementTypeResource:   "box",
+	ElementTypeChild:      "box3d",
+	ElementTypeExport:     "cds",
+}
+
+var dotEdgeStyles = map[EdgeKind]string{
+	EdgeKindReference: "solid",
+	EdgeKindDependsOn: "bold",
+	EdgeKindLink:      "dashed",
+}
+
+// RenderDOT writes the graph in the Graphviz DOT language.
+func RenderDOT(w io.Writer, graph *Graph, opts RenderOptions) error {
+	var sb strings.Builder
+	sb.WriteString("digraph blueprint {\n")
+	fmt.Fprintf(&sb, "  rankdir=%s;\n", directionOrDefault(opts.Direction))
+	sb.WriteString("  node [fontname=\"Helvetica\"];\n")
+
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(
+			&sb,
+			"  %q [label=%q, shape=%s];\n",
+			node.ID,
+			nodeLabel(node, "\n"),
+			dotNodeShapes[node.ElementType],
+		)
+	}
+
+	for _, edge := range graph.Edges {
+		attrs := fmt.Sprintf("style=%s", dotEdgeStyles[edge.Kind])
+		if edge.Kind == EdgeKindLink {
+			attrs += `, label="link"`
+		}
+		fmt.Fprintf(&sb, "  %q -> %q [%s];\n", edge.From, edge.To, attrs)
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var mermaidNodeShapes = map[ElementType][2]string{
+	ElementTypeVariable:   {"([", "])"},
+	ElementTypeValue:      {"[/", "/]"},
+	ElementTypeDataSource: {"[(", ")]"},
+	ElementTypeResource:   {"[", "]"},
+	ElementTypeChild:      {"[[", "]]"},
+	ElementTypeExport:     {">", "]"},
+}
+
+var mermaidEdgeArrows = map[EdgeKind]string{
+	EdgeKindReference: "-->",
+	EdgeKindDependsOn: "==>",
+	EdgeKindLink:      "-. link .->",
+}
+
+// RenderMermaid writes the graph as a Mermaid flowchart.
+// Node IDs are replaced with generated identifiers as element names
+// can contain characters that are not valid in Mermaid node IDs.
+func RenderMermaid(w io.Writer, graph *Graph, opts RenderOptions) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "flowchart %s\n", directionOrDefault(opts.Direction))
+
+	mermaidIDs := map[string]string{}
+	for i, node := range graph.Nodes {
+		mermaidID := fmt.Sprintf("n%d", i)
+		mermaidIDs[node.ID] = mermaidID
+		shape := mermaidNodeShapes[node.ElementType]
+		fmt.Fprintf(
+			&sb,
+			"  %s%s\"%s\"%s:::%s\n",
+			mermaidID,
+			shape[0],
+			mermaidLabel(node),
+			shape[1],
+			node.ElementType,
+		)
+	}
+
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(
+			&sb,
+			"  %s %s %s\n",
+			mermaidIDs[edge.From],
+			mermaidEdgeArrows[edge.Kind],
+			mermaidIDs[edge.To],
+		)
+	}
+
+	for _, elementType := range ElementTypes {
+		fmt.Fprintf(&sb, "  classDef %s %s\n", elementType, mermaidClassStyle(elementType))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type elementColour struct {
+	fill   string
+	stroke string
+}
+
+// elementColours holds the colours used to draw the nodes for each element type
+// in Mermaid flowcharts and the HTML viewer.
+var elementColours = map[ElementType]elementColour{
+	ElementTypeVariable:   {fill: "#e8f1fd", stroke: "#3b82f6"},
+	ElementTypeValue:      {fill: "#eefbf3", stroke: "#22c55e"},
+	ElementTypeDataSource: {fill: "#fdf6e8", stroke: "#f59e0b"},
+	ElementTypeResource:   {fill: "#f3eefe", stroke: "#8b5cf6"},
+	ElementTypeChild:      {fill: "#fdeef3", stroke: "#ec4899"},
+	ElementTypeExport:     {fill: "#eef2f5", stroke: "#64748b"},
+}
+
+func mermaidClassStyle(elementType ElementType) string {
+	colours := elementColours[elementType]
+	return fmt.Sprintf("fill:%s,stroke:%s", colours.fill, colours.stroke)
+}
+
+func nodeLabel(node *Node, separator string) string {
+	label := node.ID
+	if node.Type != "" {
+		label = fmt.Sprintf("%s%s(%s)", label, separator, node.Type)
+	}
+
+	return label
+}
+
+// mermaidLabel escapes double quotes in the node label as they would
+// otherwise end the quoted label text and produce an invalid flowchart.
+func mermaidLabel(node *Node) string {
+	label := strings.ReplaceAll(nodeLabel(node, "\n"), `"`, "#quot;")
+	return strings.ReplaceAll(label, "\n", "<br/>")
+}
+
+func directionOrDefault(direction Direction) Direction {
+	if direction == "" {
+		return DirectionLeftRight
+	}
+
+	return direction
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RenderTestSuite struct {
+	suite.Suite
+	graph *Graph
+}
+
+func TestRenderTestSuite(t *testing.T) {
+	suite.Run(t, new(RenderTestSuite))
+}
+
+func (s *RenderTestSuite) SetupTest() {
+	s.graph = &Graph{
+		Nodes: []*Node{
+			{ID: "variables.environment", Name: "environment", ElementType: ElementTypeVariable, Type: "string"},
+			{ID: "resources.ordersHandler", Name: "ordersHandler", ElementType: ElementTypeResource, Type: "aws/lambda/function"},
+			{ID: "resources.ordersTable", Name: "ordersTable", ElementType: ElementTypeResource, Type: "aws/dynamodb/table"},
+		},
+		Edges: []*Edge{
+			{From: "resources.ordersHandler", To: "resources.ordersTable", Kind: EdgeKindLink},
+			{From: "resources.ordersTable", To: "variables.environment", Kind: EdgeKindReference},
+		},
+	}
+}
+
+func (s *RenderTestSuite) Test_renders_dot() {
+	var out bytes.Buffer
+	err := Render(&out, s.graph, FormatDOT, RenderOptions{Direction: DirectionTopBottom})
+	s.Require().NoError(err)
+	s.Assert().Equal(`digraph blueprint {
+  rankdir=TB;
+  node [fontname="Helvetica"];
+  "variables.environment" [label="variables.environment\n(string)", shape=ellipse];
+  "resources.ordersHandler" [label="resources.ordersHandler\n(aws/lambda/function)", shape=box];
+  "resources.ordersTable" [label="resources.ordersTable\n(aws/dynamodb/table)", shape=box];
+  "resources.ordersHandler" -> "resources.ordersTable" [style=dashed, label="link"];
+  "resources.ordersTable" -> "variables.environment" [style=solid];
+}
+`, out.String())
+}
+
+func (s *RenderTestSuite) Test_renders_mermaid() {
+	var out bytes.Buffer
+	err := Render(&out, s.graph, FormatMermaid, RenderOptions{})
+	s.Require().NoError(err)
+	s.Assert().Contains(out.String(), "flowchart LR\n")
+	s.Assert().Contains(out.String(), `n0(["variables.environment<br/>(string)"]):::variable`)
+	s.Assert().Contains(out.String(), `n2["resources.ordersTable<br/>(aws/dynamodb/table)"]:::resource`)
+	s.Assert().Contains(out.String(), "n1 -. link .-> n2\n")
+	s.Assert().Contains(out.String(), "n2 --> n0\n")
+	s.Assert().Contains(out.String(), "classDef resource ")
+}
+
+func (s *RenderTestSuite) Test_escapes_quotes_in_mermaid_labels() {
+	graph := &Graph{
+		Nodes: []*Node{
+			{ID: `resources.orders"Handler`, Name: `orders"Handler`, ElementType: ElementTypeResource, Type: "aws/lambda/function"},
+		},
+	}
+
+	var out bytes.Buffer
+	err := Render(&out, graph, FormatMermaid, RenderOptions{})
+	s.Require().NoError(err)
+	s.Assert().Contains(out.String(), `n0["resources.orders#quot;Handler<br/>(aws/lambda/function)"]:::resource`)
+}
+
+func (s *RenderTestSuite) Test_renders_html_viewer_with_embedded_graph() {
+	var out bytes.Buffer
+	err := Render(&out, s.graph, FormatHTML, RenderOptions{Title: "orders graph"})
+	s.Require().NoError(err)
+	s.Assert().Contains(out.String(), "<title>orders graph</title>")
+	s.Assert().Contains(out.String(), `"id":"resources.ordersTable"`)
+	s.Assert().Contains(out.String(), `<option value="LR" selected>LR</option>`)
+	s.Assert().Contains(out.String(), `"resource":{"shape":"rect","fill":"#f3eefe","stroke":"#8b5cf6"}`)
+	s.Assert().NotContains(out.String(), "<script src")
+	s.Assert().NotContains(out.String(), "https://")
+}
+
+func (s *RenderTestSuite) Test_escapes_element_names_in_html_viewer() {
+	graph := &Graph{
+		Nodes: []*Node{
+			{
+				ID:          `resources.orders</script><script>alert("x")</script>`,
+				Name:        `orders</script><script>alert("x")</script>`,
+				ElementType: ElementTypeResource,
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	err := Render(&out, graph, FormatHTML, RenderOptions{})
+	s.Require().NoError(err)
+	s.Assert().NotContains(out.String(), "<script>alert")
+	s.Assert().Equal(1, strings.Count(out.String(), "</script>"))
+}
+
+func (s *RenderTestSuite) Test_parses_format_and_direction() {
+	format, err := ParseFormat("Mermaid")
+	s.Require().NoError(err)
+	s.Assert().Equal(FormatMermaid, format)
+
+	_, err = ParseFormat("svg")
+	s.Assert().ErrorContains(err, `unsupported graph format "svg"`)
+
+	direction, err := ParseDirection("tb")
+	s.Require().NoError(err)
+	s.Assert().Equal(DirectionTopBottom, direction)
+
+	_, err = ParseDirection("diagonal")
+	s.Assert().ErrorContains(err, `unsupported graph direction "diagonal"`)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+var errDeployNotApproved = errors.New(
+	"changes must be approved with the --auto-approve flag " +
+		"when deploying in a non-interactive environment",
+)
+
+// NewDeployHandler creates a new deployment handler
+// for non-interactive environments.
+// As changes can not be confirmed interactively, the staged changes
+// are only deployed when autoApprove is set.
+func NewDeployHandler(
+	deployEngine engine.DeployEngine,
+	opts *deploy.Options,
+	autoApprove bool,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		fmt.Fprintf(writer, "Staging changes for blueprint file: %s\n", opts.BlueprintFile)
+		staged, err := deploy.StageChanges(ctx, deployEngine, opts, nil, logger)
+		if err != nil {
+			return err
+		}
+
+		summary := deploy.Summarise(staged.Changes)
+		if !summary.HasChanges() {
+			fmt.Fprintln(writer, "No changes to deploy")
+			return nil
+		}
+
+		fmt.Fprintln(writer, "Changes:")
+		for _, change := range summary.Changes {
+			fmt.Fprintf(writer, "  %s\n", change)
+		}
+		fmt.Fprintf(writer, "Plan: %s\n", summary.Totals())
+
+		if !autoApprove {
+			return errDeployNotApproved
+		}
+
+		instanceID, err := deploy.Start(ctx, deployEngine, opts, staged.ChangesetID, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Deploying blueprint instance: %s\n", instanceID)
+
+		progress, err := deploy.Wait(
+			ctx,
+			deployEngine,
+			instanceID,
+			func(event *types.BlueprintInstanceEvent, _ *deploy.Progress) {
+				if element := deploy.ElementStateFromEvent(event); element != nil {
+					fmt.Fprintf(writer, "  %s\n", element)
+				}
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := progress.Err(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(
+			writer,
+			"Deployment finished: %s\n",
+			engine.InstanceStatusLabel(progress.InstanceStatus()),
+		)
+		return nil
+	})
+}
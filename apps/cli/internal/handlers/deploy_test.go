@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/container"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type DeployHandlerTestSuite struct {
+	suite.Suite
+	logger *zap.Logger
+	opts   *deploy.Options
+}
+
+func TestDeployHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DeployHandlerTestSuite))
+}
+
+func (s *DeployHandlerTestSuite) SetupTest() {
+	logger, _ := zap.NewDevelopment()
+	s.logger = logger
+	s.opts = &deploy.Options{BlueprintFile: "app.blueprint.yaml"}
+}
+
+func (s *DeployHandlerTestSuite) Test_deploys_staged_changes_when_approved() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:         &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn:   streamStagedChanges(newTableChanges()),
+		CreateBlueprintInstanceResult: &state.InstanceState{InstanceID: "instance-1"},
+		StreamBlueprintInstanceEventsFn: streamDeployEvents(
+			resourceEvent(core.ResourceStatusCreating),
+			resourceEvent(core.ResourceStatusCreated),
+			finishDeployEvent(core.InstanceStatusDeployed),
+		),
+	}
+
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, true, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Require().NoError(err)
+
+	out := buf.String()
+	s.Assert().Contains(out, "Staging changes for blueprint file: app.blueprint.yaml")
+	s.Assert().Contains(out, "+ resource ordersTable (create)")
+	s.Assert().Contains(out, "Plan: 1 to create, 0 to update, 0 to recreate, 0 to remove")
+	s.Assert().Contains(out, "Deploying blueprint instance: instance-1")
+	s.Assert().Contains(out, "resource ordersTable: creating")
+	s.Assert().Contains(out, "resource ordersTable: created")
+	s.Assert().Contains(out, "Deployment finished: deployed")
+}
+
+func (s *DeployHandlerTestSuite) Test_updates_existing_instance() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:         &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn:   streamStagedChanges(newTableChanges()),
+		CreateBlueprintInstanceErr:    errors.New("unexpected create"),
+		UpdateBlueprintInstanceResult: &state.InstanceState{InstanceID: "instance-1"},
+		StreamBlueprintInstanceEventsFn: streamDeployEvents(
+			finishDeployEvent(core.InstanceStatusUpdated),
+		),
+	}
+
+	s.opts.InstanceID = "instance-1"
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, true, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Require().NoError(err)
+	s.Assert().Contains(buf.String(), "Deployment finished: updated")
+}
+
+func (s *DeployHandlerTestSuite) Test_does_not_deploy_without_approval() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:       &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn: streamStagedChanges(newTableChanges()),
+		CreateBlueprintInstanceErr:  errors.New("unexpected create"),
+	}
+
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, false, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Assert().ErrorIs(err, errDeployNotApproved)
+	s.Assert().Contains(buf.String(), "+ resource ordersTable (create)")
+	s.Assert().NotContains(buf.String(), "Deploying blueprint instance")
+}
+
+func (s *DeployHandlerTestSuite) Test_no_changes_to_deploy() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:       &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn: streamStagedChanges(&changes.BlueprintChanges{}),
+		CreateBlueprintInstanceErr:  errors.New("unexpected create"),
+	}
+
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, true, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Require().NoError(err)
+	s.Assert().Contains(buf.String(), "No changes to deploy")
+}
+
+func (s *DeployHandlerTestSuite) Test_failed_deployment_returns_error() {
+	finish := finishDeployEvent(core.InstanceStatusDeployRollbackComplete)
+	finish.FinishEvent.FailureReasons = []string{"table already exists"}
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:           &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn:     streamStagedChanges(newTableChanges()),
+		CreateBlueprintInstanceResult:   &state.InstanceState{InstanceID: "instance-1"},
+		StreamBlueprintInstanceEventsFn: streamDeployEvents(finish),
+	}
+
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, true, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Assert().EqualError(
+		err,
+		`deployment finished with status "deployment rolled back": table already exists`,
+	)
+}
+
+func (s *DeployHandlerTestSuite) Test_create_changeset_error_propagates() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetErr: errors.New("connection refused"),
+	}
+
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, true, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Assert().Error(err)
+}
+
+func (s *DeployHandlerTestSuite) Test_deploy_stream_error_propagates() {
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:         &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn:   streamStagedChanges(newTableChanges()),
+		CreateBlueprintInstanceResult: &state.InstanceState{InstanceID: "instance-1"},
+		StreamBlueprintInstanceErr:    errors.New("stream failed"),
+	}
+
+	var buf bytes.Buffer
+	handler := NewDeployHandler(mockEngine, s.opts, true, &buf, s.logger)
+
+	err := handler.Handle(context.Background())
+	s.Assert().Error(err)
+	s.Assert().Contains(err.Error(), "stream failed")
+}
+
+func newTableChanges() *changes.BlueprintChanges {
+	return &changes.BlueprintChanges{
+		NewResources: map[string]provider.Changes{
+			"ordersTable": {},
+		},
+	}
+}
+
+func streamStagedChanges(
+	blueprintChanges *changes.BlueprintChanges,
+) func(context.Context, string, chan<- types.ChangeStagingEvent, chan<- error) error {
+	return func(
+		_ context.Context,
+		_ string,
+		streamTo chan<- types.ChangeStagingEvent,
+		_ chan<- error,
+	) error {
+		go func() {
+			streamTo <- types.ChangeStagingEvent{
+				ID: "event-1",
+				CompleteChanges: &types.CompleteChangesEventData{
+					Changes: blueprintChanges,
+				},
+			}
+			close(streamTo)
+		}()
+		return nil
+	}
+}
+
+func streamDeployEvents(
+	events ...*types.BlueprintInstanceEvent,
+) func(context.Context, string, chan<- types.BlueprintInstanceEvent, chan<- error) error {
+	return func(
+		_ context.Context,
+		_ string,
+		streamTo chan<- types.BlueprintInstanceEvent,
+		_ chan<- error,
+	) error {
+		go func() {
+			for _, event := range events {
+				streamTo <- *event
+			}
+			close(streamTo)
+		}()
+		return nil
+	}
+}
+
+func resourceEvent(status core.ResourceStatus) *types.BlueprintInstanceEvent {
+	return &types.BlueprintInstanceEvent{
+		DeployEvent: container.DeployEvent{
+			ResourceUpdateEvent: &container.ResourceDeployUpdateMessage{
+				InstanceID:   "instance-1",
+				ResourceName: "ordersTable",
+				Status:       status,
+			},
+		},
+	}
+}
+
+func finishDeployEvent(status core.InstanceStatus) *types.BlueprintInstanceEvent {
+	return &types.BlueprintInstanceEvent{
+		DeployEvent: container.DeployEvent{
+			FinishEvent: &container.DeploymentFinishedMessage{
+				InstanceID: "instance-1",
+				Status:     status,
+			},
+		},
+	}
+}
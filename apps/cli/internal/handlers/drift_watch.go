@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/drift"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// NewDriftWatchHandler creates a new handler that periodically checks
+// blueprint instances for drift in non-interactive environments.
+// The results of each check are written to the writer as they complete
+// and the notifiers are used to alert the user when new drift is detected.
+// The handler runs until the context is cancelled.
+func NewDriftWatchHandler(
+	deployEngine engine.DeployEngine,
+	targets []*drift.Target,
+	config *types.BlueprintOperationConfig,
+	interval time.Duration,
+	notifiers []drift.Notifier,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		fmt.Fprintf(
+			writer,
+			"Watching %d blueprint instance(s) for drift every %s\n",
+			len(targets),
+			interval,
+		)
+
+		tracker := drift.NewTracker()
+		for {
+			reports := drift.CheckAll(ctx, deployEngine, targets, config, logger)
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			for _, report := range reports {
+				writeDriftReport(writer, report)
+
+				newDrift := tracker.Track(report)
+				for _, resource := range newDrift {
+					fmt.Fprintf(writer, "  New drift detected: %s\n", resource.Path)
+				}
+
+				err := drift.NotifyAll(notifiers, report, newDrift)
+				if err != nil {
+					logger.Warn("failed to send drift notification", zap.Error(err))
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	})
+}
+
+func writeDriftReport(writer io.Writer, report *drift.Report) {
+	checkedAt := report.CheckedAt.Format(time.RFC3339)
+	if report.Err != nil {
+		fmt.Fprintf(
+			writer,
+			"Failed to check instance %s for drift at %s: %s\n",
+			report.Label(),
+			checkedAt,
+			report.Err,
+		)
+		return
+	}
+
+	if len(report.Resources) == 0 {
+		fmt.Fprintf(writer, "Checked instance %s at %s: no drift detected\n", report.Label(), checkedAt)
+		return
+	}
+
+	fmt.Fprintf(
+		writer,
+		"Checked instance %s at %s: %d drifted resource(s)\n",
+		report.Label(),
+		checkedAt,
+		len(report.Resources),
+	)
+	for _, resource := range report.Resources {
+		fmt.Fprintf(writer, "  %s\n", resource)
+	}
+}
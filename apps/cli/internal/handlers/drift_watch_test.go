@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/drift"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/testutils"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type DriftWatchHandlerTestSuite struct {
+	suite.Suite
+	logger *zap.Logger
+}
+
+func TestDriftWatchHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DriftWatchHandlerTestSuite))
+}
+
+func (s *DriftWatchHandlerTestSuite) SetupTest() {
+	logger, _ := zap.NewDevelopment()
+	s.logger = logger
+}
+
+func (s *DriftWatchHandlerTestSuite) Test_reports_drift_and_notifies_of_new_drift() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checks := 0
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:       &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn: streamStagedChanges(&changes.BlueprintChanges{}),
+		GetBlueprintInstanceFn: func(_ context.Context, instanceID string) (*state.InstanceState, error) {
+			checks += 1
+			instance := &state.InstanceState{
+				InstanceID:   instanceID,
+				InstanceName: "orders-api",
+				Resources:    map[string]*state.ResourceState{},
+			}
+			if checks > 1 {
+				instance.Resources["resource-1"] = &state.ResourceState{
+					Name:    "ordersTable",
+					Type:    "aws/dynamodb/table",
+					Drifted: true,
+				}
+			}
+			return instance, nil
+		},
+	}
+	notifier := &recordingNotifier{onNotify: cancel}
+
+	var buf bytes.Buffer
+	handler := NewDriftWatchHandler(
+		mockEngine,
+		[]*drift.Target{{InstanceID: "instance-1", BlueprintFile: "app.blueprint.yaml"}},
+		nil,
+		time.Millisecond,
+		[]drift.Notifier{notifier},
+		&buf,
+		s.logger,
+	)
+
+	err := handler.Handle(ctx)
+	s.Require().NoError(err)
+
+	out := buf.String()
+	s.Assert().Contains(out, "Watching 1 blueprint instance(s) for drift every 1ms")
+	s.Assert().Contains(out, "Checked instance orders-api at")
+	s.Assert().Contains(out, "no drift detected")
+	s.Assert().Contains(out, "1 drifted resource(s)")
+	s.Assert().Contains(out, "  New drift detected: ordersTable")
+	s.Assert().Equal([]string{"Drift detected in orders-api"}, notifier.titles)
+	s.Assert().Equal(2, checks)
+}
+
+func (s *DriftWatchHandlerTestSuite) Test_continues_watching_when_a_check_fails() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checks := 0
+	mockEngine := &testutils.MockDeployEngine{
+		CreateChangesetResult:       &manage.Changeset{ID: "changeset-1"},
+		StreamChangeStagingEventsFn: streamStagedChanges(&changes.BlueprintChanges{}),
+		GetBlueprintInstanceFn: func(_ context.Context, instanceID string) (*state.InstanceState, error) {
+			checks += 1
+			if checks == 1 {
+				return nil, context.DeadlineExceeded
+			}
+			if checks == 3 {
+				cancel()
+			}
+			return &state.InstanceState{
+				InstanceID: instanceID,
+				Resources: map[string]*state.ResourceState{
+					"resource-1": {Name: "ordersTable", Type: "aws/dynamodb/table", Drifted: true},
+				},
+			}, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+
+	var buf bytes.Buffer
+	handler := NewDriftWatchHandler(
+		mockEngine,
+		[]*drift.Target{{InstanceID: "instance-1", BlueprintFile: "app.blueprint.yaml"}},
+		nil,
+		time.Millisecond,
+		[]drift.Notifier{notifier},
+		&buf,
+		s.logger,
+	)
+
+	err := handler.Handle(ctx)
+	s.Require().NoError(err)
+
+	out := buf.String()
+	s.Assert().Contains(out, "Failed to check instance instance-1 for drift at")
+	s.Assert().Contains(out, "Checked instance instance-1 at")
+	s.Assert().Contains(out, "1 drifted resource(s)")
+	s.Assert().Contains(out, "  ~ resource ordersTable (aws/dynamodb/table)")
+	// The first successful check is the baseline for new drift,
+	// so drift that has not changed since is not notified.
+	s.Assert().NotContains(out, "New drift detected")
+	s.Assert().Empty(notifier.titles)
+}
+
+type recordingNotifier struct {
+	titles   []string
+	onNotify func()
+}
+
+func (n *recordingNotifier) Notify(title string, _ string) error {
+	n.titles = append(n.titles, title)
+	if n.onNotify != nil {
+		n.onNotify()
+	}
+	return nil
+}
@@ -0,0 +1,569 @@
+package plugins
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	maxChecksumsSize = 1 << 20
+	maxSignatureSize = 1 << 10
+	maxDocsSize      = 32 << 20
+	// maxBinarySize guards against archives that expand
+	// to an unreasonable size when extracted.
+	maxBinarySize = 1 << 30
+)
+
+var (
+	// ErrAlreadyInstalled is returned when the requested plugin version
+	// is already installed and a re-install has not been requested.
+	ErrAlreadyInstalled = errors.New("plugin version is already installed")
+	// ErrNotInstalled is returned when upgrading a plugin
+	// that is not installed.
+	ErrNotInstalled = errors.New("plugin is not installed")
+	// ErrDocsNotPublished is returned when downloading the documentation
+	// for a plugin release that does not publish documentation.
+	ErrDocsNotPublished = errors.New("plugin release does not publish documentation")
+)
+
+// InstallerOptions holds the options for installing plugins.
+type InstallerOptions struct {
+	Client   *http.Client
+	Platform Platform
+	// PublicKey is the ed25519 key used to verify the signature of the
+	// checksums file of each release, when set, only signed releases
+	// can be installed.
+	PublicKey ed25519.PublicKey
+}
+
+// Installer downloads plugin releases, verifies them against their
+// checksums and places the plugin executables in the plugin directory.
+type Installer struct {
+	layout    Layout
+	client    *http.Client
+	publicKey ed25519.PublicKey
+	sourceFor func(id ID) Source
+}
+
+// NewInstaller creates a new installer for the given plugin directory layout.
+func NewInstaller(layout Layout, opts InstallerOptions) *Installer {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Installer{
+		layout:    layout,
+		client:    client,
+		publicKey: opts.PublicKey,
+		sourceFor: func(id ID) Source {
+			return SourceFor(id, client, opts.Platform)
+		},
+	}
+}
+
+// Install installs a version of a plugin, the latest version is installed
+// when the version is empty.
+// Unless force is set, ErrAlreadyInstalled is returned along with the
+// existing installation when the version is already installed.
+func (i *Installer) Install(
+	ctx context.Context,
+	pluginType Type,
+	id ID,
+	version string,
+	force bool,
+) (*Installed, error) {
+	release, err := i.sourceFor(id).FindRelease(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateVersion(release.Version); err != nil {
+		return nil, err
+	}
+
+	installed := &Installed{
+		Type:    pluginType,
+		ID:      id,
+		Version: release.Version,
+		Path:    i.layout.BinaryPath(pluginType, id, release.Version),
+	}
+	if _, err := os.Stat(installed.Path); err == nil && !force {
+		return installed, ErrAlreadyInstalled
+	}
+
+	expectedChecksum, err := i.releaseChecksum(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := i.downloadArchive(ctx, release, expectedChecksum)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	err = extractPlugin(
+		archive,
+		release.ArchiveName,
+		id,
+		installed.Path,
+		i.layout.DocsPath(pluginType, id, release.Version),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return installed, nil
+}
+
+// UpgradeResult holds the outcome of upgrading a plugin.
+type UpgradeResult struct {
+	// Installed is the latest version of the plugin.
+	Installed *Installed
+	// Removed holds the versions that were replaced by the latest version.
+	Removed []*Installed
+	// UpToDate is set when the latest version, or a newer version,
+	// was already installed.
+	UpToDate bool
+}
+
+// Upgrade installs the latest version of an installed plugin of the given type
+// and then removes the older versions it replaces.
+// Versions of the same plugin installed as a different type are left alone,
+// as are installed versions that are newer than the latest release.
+func (i *Installer) Upgrade(ctx context.Context, pluginType Type, id ID) (*UpgradeResult, error) {
+	existing, err := i.layout.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	existing = slices.DeleteFunc(existing, func(plugin *Installed) bool {
+		return plugin.Type != pluginType
+	})
+	if len(existing) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotInstalled, pluginType, id)
+	}
+
+	release, err := i.sourceFor(id).FindRelease(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	newest := existing[len(existing)-1]
+	if CompareVersions(newest.Version, release.Version) >= 0 {
+		return &UpgradeResult{Installed: newest, UpToDate: true}, nil
+	}
+
+	installed, err := i.Install(ctx, pluginType, id, release.Version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{Installed: installed}
+	for _, previous := range existing {
+		if CompareVersions(previous.Version, installed.Version) >= 0 {
+			continue
+		}
+		if err := i.layout.Remove(previous); err != nil {
+			return nil, err
+		}
+		result.Removed = append(result.Removed, previous)
+	}
+
+	return result, nil
+}
+
+// DownloadDocs downloads the documentation published with the release of an
+// installed plugin version, for plugins that do not include the documentation
+// in their release archives. ErrDocsNotPublished is returned when the release
+// does not publish documentation.
+// The documentation is verified against the release checksums when
+// the checksums include the documentation file.
+func (i *Installer) DownloadDocs(ctx context.Context, plugin *Installed) ([]byte, error) {
+	release, err := i.sourceFor(plugin.ID).FindRelease(ctx, plugin.ID, plugin.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.DocsURL == "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrDocsNotPublished, plugin.ID, plugin.Version)
+	}
+
+	docs, err := i.download(ctx, release.DocsURL, maxDocsSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.ChecksumsURL == "" {
+		return docs, nil
+	}
+
+	checksums, err := i.releaseChecksums(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	docsFileName := docsFileNameFromURL(release.DocsURL)
+	expectedChecksum, err := findChecksum(checksums, docsFileName)
+	if err != nil {
+		// Documentation is not executed so it is accepted
+		// when it is not listed in the release checksums.
+		return docs, nil
+	}
+
+	checksum := sha256.Sum256(docs)
+	if !bytes.Equal(checksum[:], expectedChecksum) {
+		return nil, fmt.Errorf("the checksum of %s does not match the release checksums", docsFileName)
+	}
+
+	return docs, nil
+}
+
+func docsFileNameFromURL(docsURL string) string {
+	parsed, err := url.Parse(docsURL)
+	if err != nil {
+		return path.Base(docsURL)
+	}
+
+	return path.Base(parsed.Path)
+}
+
+// VerifiesSignatures reports whether the installer verifies the signatures
+// of releases, when it does not, releases are only verified against checksums
+// published alongside the release archive which does not detect a compromised
+// registry or release.
+func (i *Installer) VerifiesSignatures() bool {
+	return i.publicKey != nil
+}
+
+func (i *Installer) releaseChecksum(ctx context.Context, release *Release) ([]byte, error) {
+	checksums, err := i.releaseChecksums(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	return findChecksum(checksums, release.ArchiveName)
+}
+
+// releaseChecksums downloads the checksums file for a release,
+// verifying its signature when a public key is configured.
+func (i *Installer) releaseChecksums(ctx context.Context, release *Release) ([]byte, error) {
+	if release.ChecksumsURL == "" {
+		return nil, fmt.Errorf(
+			"release %s does not include a checksums file, the plugin can not be verified",
+			release.Version,
+		)
+	}
+
+	checksums, err := i.download(ctx, release.ChecksumsURL, maxChecksumsSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.publicKey != nil {
+		if err := i.verifySignature(ctx, release, checksums); err != nil {
+			return nil, err
+		}
+	}
+
+	return checksums, nil
+}
+
+func (i *Installer) verifySignature(ctx context.Context, release *Release, checksums []byte) error {
+	if release.SignatureURL == "" {
+		return fmt.Errorf(
+			"release %s is not signed, a signature is required when a public key is configured",
+			release.Version,
+		)
+	}
+
+	signature, err := i.download(ctx, release.SignatureURL, maxSignatureSize)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := decodeSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(i.publicKey, checksums, decoded) {
+		return fmt.Errorf("the signature of the checksums for release %s is not valid", release.Version)
+	}
+
+	return nil
+}
+
+// decodeSignature accepts raw and base64 encoded signatures.
+func decodeSignature(signature []byte) ([]byte, error) {
+	if len(signature) == ed25519.SignatureSize {
+		return signature, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(decoded) != ed25519.SignatureSize {
+		return nil, errors.New("the checksums signature is not a valid ed25519 signature")
+	}
+
+	return decoded, nil
+}
+
+// findChecksum finds the checksum for a file in a checksums file
+// in the format produced by sha256sum, e.g. "{hex}  {file name}".
+func findChecksum(checksums []byte, fileName string) ([]byte, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != fileName {
+			continue
+		}
+
+		return hex.DecodeString(fields[0])
+	}
+
+	return nil, fmt.Errorf("no checksum found for %s", fileName)
+}
+
+func (i *Installer) download(ctx context.Context, target string, limit int64) ([]byte, error) {
+	body, err := i.open(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(io.LimitReader(body, limit))
+}
+
+// downloadArchive downloads the release archive to a temporary file,
+// returning the file once its checksum has been verified.
+func (i *Installer) downloadArchive(
+	ctx context.Context,
+	release *Release,
+	expectedChecksum []byte,
+) (*os.File, error) {
+	body, err := i.open(ctx, release.ArchiveURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	archive, err := os.CreateTemp("", "celerity-plugin-*")
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(archive, hash), body)
+	if err == nil && !bytes.Equal(hash.Sum(nil), expectedChecksum) {
+		err = fmt.Errorf("the checksum of %s does not match the release checksums", release.ArchiveName)
+	}
+	if err == nil {
+		_, err = archive.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		archive.Close()
+		os.Remove(archive.Name())
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func (i *Installer) open(ctx context.Context, target string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download of %s failed with status %d", target, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// extractPlugin finds the plugin executable in the archive and writes it to
+// the destination. The executable is the file named "plugin" or named after
+// the plugin, or the only file when the archive holds a single file.
+// The plugin documentation is written to docsDest when the archive includes
+// a docs.json file, a previously installed docs file is removed otherwise.
+// Paths in the archive are never used to write files.
+func extractPlugin(archive *os.File, archiveName string, id ID, dest string, docsDest string) error {
+	files, err := archiveFiles(archive, archiveName)
+	if err != nil {
+		return err
+	}
+
+	var binary *archiveFile
+	var docs *archiveFile
+	for _, file := range files {
+		if path.Base(file.name) == DocsFileName {
+			docs = file
+			continue
+		}
+
+		name := strings.TrimSuffix(path.Base(file.name), ".exe")
+		if binary == nil && (name == binaryName || name == id.Name) {
+			binary = file
+		}
+	}
+	if binary == nil && docs == nil && len(files) == 1 {
+		binary = files[0]
+	}
+	if binary == nil {
+		return fmt.Errorf("could not find the plugin executable in %s", archiveName)
+	}
+
+	if err := extractFile(binary, dest, 0o755); err != nil {
+		return err
+	}
+
+	if docs == nil {
+		if err := os.Remove(docsDest); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	return extractFile(docs, docsDest, 0o644)
+}
+
+func extractFile(file *archiveFile, dest string, mode os.FileMode) error {
+	content, err := file.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	return writeFile(io.LimitReader(content, maxBinarySize), dest, mode)
+}
+
+type archiveFile struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+func archiveFiles(archive *os.File, archiveName string) ([]*archiveFile, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		return zipFiles(archive)
+	}
+
+	if strings.HasSuffix(archiveName, ".tar.gz") || strings.HasSuffix(archiveName, ".tgz") {
+		return tarGzFiles(archive)
+	}
+
+	return nil, fmt.Errorf("unsupported archive format for %s", archiveName)
+}
+
+func zipFiles(archive *os.File) ([]*archiveFile, error) {
+	info, err := archive.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := zip.NewReader(archive, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*archiveFile{}
+	for _, file := range reader.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, &archiveFile{name: file.Name, open: file.Open})
+	}
+
+	return files, nil
+}
+
+// tarGzFiles reads the regular files in a gzipped tarball into memory
+// as tar archives can only be read sequentially.
+func tarGzFiles(archive *os.File) ([]*archiveFile, error) {
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	files := []*archiveFile{}
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(reader, maxBinarySize))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &archiveFile{
+			name: header.Name,
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(content)), nil
+			},
+		})
+	}
+}
+
+// writeFile writes the content to a temporary file next to the
+// destination and renames it into place so the plugin launcher never
+// sees a partially written executable.
+func writeFile(content io.Reader, dest string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".plugin-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
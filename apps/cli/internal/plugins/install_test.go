@@ -0,0 +1,343 @@
+package plugins
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InstallTestSuite struct {
+	suite.Suite
+	layout   Layout
+	server   *httptest.Server
+	files    map[string][]byte
+	versions []string
+	platform Platform
+	aws      ID
+}
+
+func TestInstallTestSuite(t *testing.T) {
+	suite.Run(t, new(InstallTestSuite))
+}
+
+func (s *InstallTestSuite) SetupTest() {
+	s.layout = Layout{Dir: s.T().TempDir()}
+	s.files = map[string][]byte{}
+	s.versions = []string{}
+	s.platform = Platform{OS: "linux", Arch: "amd64"}
+	s.aws = ID{Host: DefaultRegistryHost, Namespace: "bluelink", Name: "aws"}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+}
+
+func (s *InstallTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *InstallTestSuite) Test_installs_latest_version_from_registry() {
+	s.publishRegistryRelease("1.2.0", tarGz(map[string]string{"README.md": "docs", "plugin": "aws-1.2.0"}))
+	s.publishRegistryRelease("1.10.0", tarGz(map[string]string{"plugin": "aws-1.10.0", "docs/docs.json": "{}"}))
+
+	installed, err := s.registryInstaller(nil).Install(context.Background(), TypeProvider, s.aws, "", false)
+	s.Require().NoError(err)
+	s.Assert().Equal("1.10.0", installed.Version)
+	s.Assert().Equal(s.layout.BinaryPath(TypeProvider, s.aws, "1.10.0"), installed.Path)
+
+	content, err := os.ReadFile(installed.Path)
+	s.Require().NoError(err)
+	s.Assert().Equal("aws-1.10.0", string(content))
+
+	info, err := os.Stat(installed.Path)
+	s.Require().NoError(err)
+	s.Assert().Equal(os.FileMode(0o755), info.Mode().Perm())
+
+	docs, err := os.ReadFile(s.layout.DocsPath(TypeProvider, s.aws, "1.10.0"))
+	s.Require().NoError(err)
+	s.Assert().Equal("{}", string(docs))
+
+	_, err = s.registryInstaller(nil).Install(context.Background(), TypeProvider, s.aws, "1.10.0", false)
+	s.Assert().ErrorIs(err, ErrAlreadyInstalled)
+}
+
+func (s *InstallTestSuite) Test_rejects_archive_with_mismatched_checksum() {
+	s.publishRegistryRelease("1.0.0", tarGz(map[string]string{"plugin": "aws"}))
+	s.files["/files/aws_1.0.0_linux_amd64.tar.gz"] = tarGz(map[string]string{"plugin": "tampered"})
+
+	_, err := s.registryInstaller(nil).Install(context.Background(), TypeProvider, s.aws, "1.0.0", false)
+	s.Assert().ErrorContains(err, "does not match the release checksums")
+
+	installed, err := s.layout.List()
+	s.Require().NoError(err)
+	s.Assert().Empty(installed)
+}
+
+func (s *InstallTestSuite) Test_verifies_signature_when_public_key_is_configured() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	s.publishRegistryRelease("1.0.0", tarGz(map[string]string{"plugin": "aws"}))
+	_, err = s.registryInstaller(publicKey).Install(context.Background(), TypeProvider, s.aws, "1.0.0", false)
+	s.Assert().ErrorContains(err, "checksums.txt.sig failed with status 404")
+
+	checksums := s.files["/files/aws_1.0.0_checksums.txt"]
+	s.files["/files/aws_1.0.0_checksums.txt.sig"] = []byte(
+		base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, checksums)),
+	)
+	_, err = s.registryInstaller(publicKey).Install(context.Background(), TypeProvider, s.aws, "1.0.0", false)
+	s.Require().NoError(err)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	_, err = s.registryInstaller(otherKey).Install(context.Background(), TypeProvider, s.aws, "1.0.0", true)
+	s.Assert().ErrorContains(err, "signature of the checksums for release 1.0.0 is not valid")
+}
+
+func (s *InstallTestSuite) Test_installs_from_github_release_zip() {
+	archive := zipArchive(map[string]string{"bluelink-provider-aws.exe": "aws-from-github"})
+	s.files["/files/bluelink-provider-aws_2.0.0_linux_amd64.zip"] = archive
+	s.files["/files/bluelink-provider-aws_2.0.0_checksums.txt"] = checksumsFile(
+		"bluelink-provider-aws_2.0.0_linux_amd64.zip",
+		archive,
+	)
+	s.files["/repos/newstack-cloud/bluelink-provider-aws/releases/tags/v2.0.0"] = s.githubRelease(
+		"v2.0.0",
+		"bluelink-provider-aws_2.0.0_darwin_arm64.zip",
+		"bluelink-provider-aws_2.0.0_linux_amd64.zip",
+		"bluelink-provider-aws_2.0.0_checksums.txt",
+	)
+
+	id := ID{Host: GitHubHost, Namespace: "newstack-cloud", Name: "bluelink-provider-aws"}
+	installer := NewInstaller(s.layout, InstallerOptions{Client: s.server.Client(), Platform: s.platform})
+	installer.sourceFor = func(ID) Source {
+		return &githubSource{client: s.server.Client(), baseURL: s.server.URL, platform: s.platform}
+	}
+
+	installed, err := installer.Install(context.Background(), TypeProvider, id, "2.0.0", false)
+	s.Require().NoError(err)
+	s.Assert().Equal("2.0.0", installed.Version)
+	content, err := os.ReadFile(installed.Path)
+	s.Require().NoError(err)
+	s.Assert().Equal("aws-from-github", string(content))
+}
+
+func (s *InstallTestSuite) Test_downloads_docs_published_with_release() {
+	archive := zipArchive(map[string]string{"bluelink-provider-aws": "aws-from-github"})
+	docs := []byte(`{"id": "newstack-cloud/bluelink-provider-aws"}`)
+	s.files["/files/bluelink-provider-aws_2.0.0_linux_amd64.zip"] = archive
+	s.files["/files/bluelink-provider-aws_2.0.0_docs.json"] = docs
+	s.files["/files/bluelink-provider-aws_2.0.0_checksums.txt"] = checksumsFile(
+		"bluelink-provider-aws_2.0.0_docs.json",
+		docs,
+	)
+	s.files["/repos/newstack-cloud/bluelink-provider-aws/releases/tags/v2.0.0"] = s.githubRelease(
+		"v2.0.0",
+		"bluelink-provider-aws_2.0.0_linux_amd64.zip",
+		"bluelink-provider-aws_2.0.0_docs.json",
+		"bluelink-provider-aws_2.0.0_checksums.txt",
+	)
+
+	id := ID{Host: GitHubHost, Namespace: "newstack-cloud", Name: "bluelink-provider-aws"}
+	installer := NewInstaller(s.layout, InstallerOptions{Client: s.server.Client(), Platform: s.platform})
+	installer.sourceFor = func(ID) Source {
+		return &githubSource{client: s.server.Client(), baseURL: s.server.URL, platform: s.platform}
+	}
+	plugin := &Installed{Type: TypeProvider, ID: id, Version: "2.0.0"}
+
+	downloaded, err := installer.DownloadDocs(context.Background(), plugin)
+	s.Require().NoError(err)
+	s.Assert().Equal(docs, downloaded)
+
+	s.files["/files/bluelink-provider-aws_2.0.0_docs.json"] = []byte(`{"id": "tampered"}`)
+	_, err = installer.DownloadDocs(context.Background(), plugin)
+	s.Assert().ErrorContains(err, "checksum of bluelink-provider-aws_2.0.0_docs.json does not match")
+
+	s.publishRegistryRelease("1.0.0", tarGz(map[string]string{"plugin": "aws"}))
+	_, err = s.registryInstaller(nil).DownloadDocs(
+		context.Background(),
+		&Installed{Type: TypeProvider, ID: s.aws, Version: "1.0.0"},
+	)
+	s.Assert().ErrorIs(err, ErrDocsNotPublished)
+}
+
+func (s *InstallTestSuite) Test_upgrades_to_latest_version_and_removes_previous_versions() {
+	s.publishRegistryRelease("1.0.0", tarGz(map[string]string{"plugin": "aws-1.0.0"}))
+	installer := s.registryInstaller(nil)
+
+	_, err := installer.Upgrade(context.Background(), TypeProvider, s.aws)
+	s.Assert().ErrorIs(err, ErrNotInstalled)
+
+	_, err = installer.Install(context.Background(), TypeProvider, s.aws, "1.0.0", false)
+	s.Require().NoError(err)
+
+	result, err := installer.Upgrade(context.Background(), TypeProvider, s.aws)
+	s.Require().NoError(err)
+	s.Assert().True(result.UpToDate)
+
+	s.publishRegistryRelease("1.1.0", tarGz(map[string]string{"plugin": "aws-1.1.0"}))
+	result, err = installer.Upgrade(context.Background(), TypeProvider, s.aws)
+	s.Require().NoError(err)
+	s.Assert().False(result.UpToDate)
+	s.Assert().Equal("1.1.0", result.Installed.Version)
+	s.Require().Len(result.Removed, 1)
+	s.Assert().Equal("1.0.0", result.Removed[0].Version)
+
+	installed, err := s.layout.Find(s.aws)
+	s.Require().NoError(err)
+	s.Require().Len(installed, 1)
+	s.Assert().Equal("1.1.0", installed[0].Version)
+}
+
+func (s *InstallTestSuite) Test_upgrade_leaves_other_plugin_types_and_newer_versions_installed() {
+	s.publishRegistryRelease("1.0.0", tarGz(map[string]string{"plugin": "aws-1.0.0"}))
+	s.publishRegistryRelease("1.1.0", tarGz(map[string]string{"plugin": "aws-1.1.0"}))
+	installer := s.registryInstaller(nil)
+
+	_, err := installer.Install(context.Background(), TypeProvider, s.aws, "1.0.0", false)
+	s.Require().NoError(err)
+	_, err = installer.Install(context.Background(), TypeTransformer, s.aws, "1.0.0", false)
+	s.Require().NoError(err)
+
+	result, err := installer.Upgrade(context.Background(), TypeProvider, s.aws)
+	s.Require().NoError(err)
+	s.Assert().Equal("1.1.0", result.Installed.Version)
+	s.Require().Len(result.Removed, 1)
+	s.Assert().Equal(TypeProvider, result.Removed[0].Type)
+
+	_, err = os.Stat(s.layout.BinaryPath(TypeTransformer, s.aws, "1.0.0"))
+	s.Assert().NoError(err)
+
+	localBuild := s.layout.BinaryPath(TypeProvider, s.aws, "1.2.0-dev")
+	s.Require().NoError(os.MkdirAll(filepath.Dir(localBuild), 0o755))
+	s.Require().NoError(os.WriteFile(localBuild, []byte("aws-dev"), 0o755))
+
+	result, err = installer.Upgrade(context.Background(), TypeProvider, s.aws)
+	s.Require().NoError(err)
+	s.Assert().True(result.UpToDate)
+	s.Assert().Equal("1.2.0-dev", result.Installed.Version)
+
+	installed, err := s.layout.Find(s.aws)
+	s.Require().NoError(err)
+	s.Assert().Len(installed, 3)
+}
+
+func (s *InstallTestSuite) registryInstaller(publicKey ed25519.PublicKey) *Installer {
+	installer := NewInstaller(s.layout, InstallerOptions{
+		Client:    s.server.Client(),
+		Platform:  s.platform,
+		PublicKey: publicKey,
+	})
+	installer.sourceFor = func(ID) Source {
+		return &registrySource{client: s.server.Client(), baseURL: s.server.URL, platform: s.platform}
+	}
+	return installer
+}
+
+func (s *InstallTestSuite) publishRegistryRelease(version string, archive []byte) {
+	archiveName := fmt.Sprintf("aws_%s_linux_amd64.tar.gz", version)
+	checksumsName := fmt.Sprintf("aws_%s_checksums.txt", version)
+	s.files["/files/"+archiveName] = archive
+	s.files["/files/"+checksumsName] = checksumsFile(archiveName, archive)
+
+	download, err := json.Marshal(registryDownload{
+		Filename:            archiveName,
+		DownloadURL:         s.server.URL + "/files/" + archiveName,
+		ShasumsURL:          s.server.URL + "/files/" + checksumsName,
+		ShasumsSignatureURL: s.server.URL + "/files/" + checksumsName + ".sig",
+	})
+	s.Require().NoError(err)
+	s.files["/v1/plugins/bluelink/aws/"+version+"/download/linux/amd64"] = download
+
+	s.versions = append(s.versions, version)
+	versions, err := json.Marshal(registryVersions{Versions: s.versions})
+	s.Require().NoError(err)
+	s.files["/v1/plugins/bluelink/aws/versions"] = versions
+}
+
+func (s *InstallTestSuite) githubRelease(tag string, assetNames ...string) []byte {
+	release := githubRelease{TagName: tag}
+	for _, name := range assetNames {
+		release.Assets = append(release.Assets, githubAsset{
+			Name:               name,
+			BrowserDownloadURL: s.server.URL + "/files/" + name,
+		})
+	}
+
+	content, err := json.Marshal(release)
+	s.Require().NoError(err)
+	return content
+}
+
+func (s *InstallTestSuite) serve(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.files[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(content)
+}
+
+func checksumsFile(archiveName string, archive []byte) []byte {
+	sum := sha256.Sum256(archive)
+	return fmt.Appendf(
+		nil,
+		"%s  other_file.tar.gz\n%s  %s\n",
+		strings.Repeat("0", 64),
+		hex.EncodeToString(sum[:]),
+		archiveName,
+	)
+}
+
+func tarGz(files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		must(tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tarWriter.Write([]byte(content))
+		must(err)
+	}
+	must(tarWriter.Close())
+	must(gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func zipArchive(files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range files {
+		writer, err := zipWriter.Create(name)
+		must(err)
+		_, err = writer.Write([]byte(content))
+		must(err)
+	}
+	must(zipWriter.Close())
+	return buf.Bytes()
+}
+
+func must(err error) {
+	if err != nil {
+		panic(errors.Join(errors.New("failed to build test archive"), err))
+	}
+}
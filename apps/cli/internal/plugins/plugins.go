@@ -0,0 +1,251 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultRegistryHost is the host of the plugin registry used for
+	// plugin IDs that do not include a host, e.g. "bluelink/aws".
+	DefaultRegistryHost = "registry.bluelink.dev"
+	// GitHubHost is the host used in plugin IDs for plugins installed from
+	// GitHub releases, e.g. "github.com/newstack-cloud/bluelink-provider-aws".
+	GitHubHost = "github.com"
+	// PluginPathEnvVar is the environment variable the deploy engine plugin
+	// launcher uses to find plugins, it holds a list of directories.
+	PluginPathEnvVar = "BLUELINK_DEPLOY_ENGINE_PLUGIN_PATH"
+	// DocsFileName is the name of the file holding the documentation
+	// for a plugin version, in the JSON format produced by the plugin
+	// docs generator. Plugin release archives can include this file
+	// and it is placed alongside the plugin executable.
+	DocsFileName = "docs.json"
+	// binaryName is the name of the plugin executable
+	// expected by the plugin launcher in each version directory.
+	binaryName = "plugin"
+)
+
+// Type is the kind of plugin, this determines where
+// the plugin is placed in the plugin directory.
+type Type string
+
+const (
+	// TypeProvider is a provider plugin.
+	TypeProvider Type = "provider"
+	// TypeTransformer is a transformer plugin.
+	TypeTransformer Type = "transformer"
+)
+
+// Types lists the supported plugin types.
+var Types = []Type{
+	TypeProvider,
+	TypeTransformer,
+}
+
+// ParseType parses a plugin type from its name.
+func ParseType(value string) (Type, error) {
+	if slices.Contains(Types, Type(value)) {
+		return Type(value), nil
+	}
+
+	return "", fmt.Errorf(
+		"unsupported plugin type %q, must be one of %q, %q",
+		value,
+		TypeProvider,
+		TypeTransformer,
+	)
+}
+
+func (t Type) dirName() string {
+	return string(t) + "s"
+}
+
+// ID identifies a plugin by the host it is distributed from,
+// the namespace it belongs to and its name.
+type ID struct {
+	Host      string
+	Namespace string
+	Name      string
+}
+
+func (id ID) String() string {
+	return id.Host + "/" + id.Namespace + "/" + id.Name
+}
+
+// ParseID parses a plugin ID in the form "[host/]namespace/name[@version]",
+// the default registry host is used when the host is omitted.
+// The version is empty when it is not included.
+func ParseID(value string) (ID, string, error) {
+	idValue, version, _ := strings.Cut(strings.TrimSpace(value), "@")
+	parts := strings.Split(idValue, "/")
+	if len(parts) == 2 {
+		parts = append([]string{DefaultRegistryHost}, parts...)
+	}
+
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return ID{}, "", fmt.Errorf(
+			"invalid plugin ID %q, must be in the form \"[host/]namespace/name[@version]\"",
+			value,
+		)
+	}
+
+	for _, part := range parts {
+		if part == "." || part == ".." {
+			return ID{}, "", fmt.Errorf("invalid plugin ID %q", value)
+		}
+	}
+
+	version = normaliseVersion(version)
+	if version != "" {
+		if err := validateVersion(version); err != nil {
+			return ID{}, "", err
+		}
+	}
+
+	return ID{Host: parts[0], Namespace: parts[1], Name: parts[2]}, version, nil
+}
+
+// DefaultDir finds the directory plugins are installed to by default,
+// this is the first directory in the deploy engine's plugin path when set,
+// otherwise the default plugin directory in the user's home directory.
+func DefaultDir() (string, error) {
+	for _, dir := range filepath.SplitList(os.Getenv(PluginPathEnvVar)) {
+		if dir != "" {
+			return dir, nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".bluelink", "engine", "plugins", "bin"), nil
+}
+
+// Installed holds information about a plugin version
+// installed in the plugin directory.
+type Installed struct {
+	Type    Type
+	ID      ID
+	Version string
+	// Path is the path of the plugin executable.
+	Path string
+}
+
+// Layout determines where plugins are placed in a plugin directory,
+// the layout matches what the plugin launcher expects:
+// {dir}/{providers|transformers}/{host}/{namespace}/{name}/{version}/plugin
+type Layout struct {
+	Dir string
+}
+
+// VersionDir is the directory a plugin version is installed to.
+func (l Layout) VersionDir(pluginType Type, id ID, version string) string {
+	return filepath.Join(l.pluginDir(pluginType, id), version)
+}
+
+// BinaryPath is the path of the executable for a plugin version.
+func (l Layout) BinaryPath(pluginType Type, id ID, version string) string {
+	return filepath.Join(l.VersionDir(pluginType, id, version), binaryName)
+}
+
+// DocsPath is the path of the documentation for a plugin version,
+// the file only exists when the plugin release included documentation.
+func (l Layout) DocsPath(pluginType Type, id ID, version string) string {
+	return filepath.Join(l.VersionDir(pluginType, id, version), DocsFileName)
+}
+
+func (l Layout) pluginDir(pluginType Type, id ID) string {
+	return filepath.Join(l.Dir, pluginType.dirName(), id.Host, id.Namespace, id.Name)
+}
+
+// List finds all the plugin versions installed in the plugin directory,
+// ordered by type, ID and version.
+func (l Layout) List() ([]*Installed, error) {
+	installed := []*Installed{}
+	for _, pluginType := range Types {
+		typeDir := filepath.Join(l.Dir, pluginType.dirName())
+		err := filepath.WalkDir(typeDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+
+			if entry.IsDir() || entry.Name() != binaryName {
+				return nil
+			}
+
+			rel, err := filepath.Rel(typeDir, path)
+			if err != nil {
+				return err
+			}
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			if len(parts) != 5 {
+				return nil
+			}
+
+			installed = append(installed, &Installed{
+				Type:    pluginType,
+				ID:      ID{Host: parts[0], Namespace: parts[1], Name: parts[2]},
+				Version: parts[3],
+				Path:    path,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(installed, func(a, b *Installed) int {
+		if a.Type != b.Type {
+			return strings.Compare(string(a.Type), string(b.Type))
+		}
+		if a.ID != b.ID {
+			return strings.Compare(a.ID.String(), b.ID.String())
+		}
+		return CompareVersions(a.Version, b.Version)
+	})
+	return installed, nil
+}
+
+// Find lists the installed versions of the plugin with the given ID,
+// ordered from the oldest to the newest version.
+func (l Layout) Find(id ID) ([]*Installed, error) {
+	installed, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(installed, func(plugin *Installed) bool {
+		return plugin.ID != id
+	}), nil
+}
+
+// Remove removes an installed plugin version along with any
+// directories in the layout that are left empty.
+func (l Layout) Remove(plugin *Installed) error {
+	versionDir := filepath.Dir(plugin.Path)
+	if err := os.RemoveAll(versionDir); err != nil {
+		return err
+	}
+
+	typeDir := filepath.Join(l.Dir, plugin.Type.dirName())
+	for dir := filepath.Dir(versionDir); dir != typeDir; dir = filepath.Dir(dir) {
+		// Removing a directory that is not empty fails,
+		// which is where the clean up stops.
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PluginsTestSuite struct {
+	suite.Suite
+	layout Layout
+}
+
+func TestPluginsTestSuite(t *testing.T) {
+	suite.Run(t, new(PluginsTestSuite))
+}
+
+func (s *PluginsTestSuite) SetupTest() {
+	s.layout = Layout{Dir: s.T().TempDir()}
+}
+
+func (s *PluginsTestSuite) Test_parses_plugin_ids() {
+	id, version, err := ParseID("bluelink/aws")
+	s.Require().NoError(err)
+	s.Assert().Equal(ID{Host: DefaultRegistryHost, Namespace: "bluelink", Name: "aws"}, id)
+	s.Assert().Empty(version)
+
+	id, version, err = ParseID("github.com/newstack-cloud/bluelink-provider-aws@v1.2.0")
+	s.Require().NoError(err)
+	s.Assert().Equal("github.com/newstack-cloud/bluelink-provider-aws", id.String())
+	s.Assert().Equal("1.2.0", version)
+
+	for _, invalid := range []string{"aws", "a/b/c/d", "bluelink//aws", "bluelink/../aws", "bluelink/aws@../1"} {
+		_, _, err := ParseID(invalid)
+		s.Assert().Error(err, invalid)
+	}
+}
+
+func (s *PluginsTestSuite) Test_parses_plugin_types() {
+	pluginType, err := ParseType("transformer")
+	s.Require().NoError(err)
+	s.Assert().Equal(TypeTransformer, pluginType)
+
+	_, err = ParseType("function")
+	s.Assert().EqualError(err, `unsupported plugin type "function", must be one of "provider", "transformer"`)
+}
+
+func (s *PluginsTestSuite) Test_compares_versions() {
+	s.Assert().Equal(0, CompareVersions("1.2.0", "v1.2.0"))
+	s.Assert().Equal(-1, CompareVersions("1.2.0", "1.10.0"))
+	s.Assert().Equal(1, CompareVersions("2.0.0", "1.99.99"))
+	s.Assert().Equal(-1, CompareVersions("1.0.0-beta.2", "1.0.0"))
+	s.Assert().Equal(-1, CompareVersions("1.0.0-beta.2", "1.0.0-beta.10"))
+	s.Assert().Equal(0, CompareVersions("1.0.0+build.1", "1.0.0"))
+}
+
+func (s *PluginsTestSuite) Test_lists_installed_plugins_in_launcher_layout() {
+	aws := ID{Host: DefaultRegistryHost, Namespace: "bluelink", Name: "aws"}
+	celerity := ID{Host: GitHubHost, Namespace: "newstack-cloud", Name: "celerity-transformer"}
+	s.install(TypeProvider, aws, "1.10.0")
+	s.install(TypeProvider, aws, "1.2.0")
+	s.install(TypeTransformer, celerity, "0.1.0")
+
+	s.Assert().Equal(
+		filepath.Join(s.layout.Dir, "providers", DefaultRegistryHost, "bluelink", "aws", "1.2.0", "plugin"),
+		s.layout.BinaryPath(TypeProvider, aws, "1.2.0"),
+	)
+
+	installed, err := s.layout.List()
+	s.Require().NoError(err)
+	s.Require().Len(installed, 3)
+	s.Assert().Equal("1.2.0", installed[0].Version)
+	s.Assert().Equal("1.10.0", installed[1].Version)
+	s.Assert().Equal(TypeTransformer, installed[2].Type)
+	s.Assert().Equal(celerity, installed[2].ID)
+
+	found, err := s.layout.Find(aws)
+	s.Require().NoError(err)
+	s.Assert().Len(found, 2)
+}
+
+func (s *PluginsTestSuite) Test_removes_plugin_and_empty_directories() {
+	aws := ID{Host: DefaultRegistryHost, Namespace: "bluelink", Name: "aws"}
+	gcp := ID{Host: DefaultRegistryHost, Namespace: "bluelink", Name: "gcp"}
+	s.install(TypeProvider, aws, "1.0.0")
+	s.install(TypeProvider, gcp, "1.0.0")
+
+	installed, err := s.layout.Find(aws)
+	s.Require().NoError(err)
+	s.Require().NoError(s.layout.Remove(installed[0]))
+
+	_, err = os.Stat(filepath.Join(s.layout.Dir, "providers", DefaultRegistryHost, "bluelink", "aws"))
+	s.Assert().True(os.IsNotExist(err))
+	_, err = os.Stat(s.layout.BinaryPath(TypeProvider, gcp, "1.0.0"))
+	s.Assert().NoError(err)
+}
+
+func (s *PluginsTestSuite) install(pluginType Type, id ID, version string) {
+	path := s.layout.BinaryPath(pluginType, id, version)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte("binary"), 0o755))
+}
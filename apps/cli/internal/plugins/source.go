@@ -0,0 +1,280 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrVersionNotFound is returned when the requested version
+// of a plugin is not available from its source.
+var ErrVersionNotFound = errors.New("plugin version not found")
+
+// Release holds the locations of the files for a plugin version
+// built for the current OS and architecture.
+type Release struct {
+	Version string
+	// ArchiveName is the file name of the archive, the extension
+	// determines how the archive is extracted.
+	ArchiveName string
+	ArchiveURL  string
+	// ChecksumsURL is the location of a checksums file that holds
+	// the SHA-256 checksum of the archive.
+	ChecksumsURL string
+	// SignatureURL is the location of an ed25519 signature of the
+	// checksums file, this is empty when the release is not signed.
+	SignatureURL string
+	// DocsURL is the location of the plugin documentation in the JSON format
+	// produced by the plugin docs generator, this is empty when the release
+	// does not publish documentation separately from the archive.
+	DocsURL string
+}
+
+// Source finds the releases of a plugin.
+type Source interface {
+	// FindRelease finds the release of the plugin for the given version,
+	// the latest release is found when the version is empty.
+	FindRelease(ctx context.Context, id ID, version string) (*Release, error)
+}
+
+// Platform is the OS and architecture plugin binaries are downloaded for.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// SourceFor selects the source for a plugin based on the host in its ID,
+// plugins with the GitHub host are installed from GitHub releases and
+// all other hosts are treated as plugin registries.
+func SourceFor(id ID, client *http.Client, platform Platform) Source {
+	if id.Host == GitHubHost {
+		return &githubSource{
+			client:   client,
+			baseURL:  "https://api.github.com",
+			platform: platform,
+		}
+	}
+
+	return &registrySource{
+		client:   client,
+		baseURL:  "https://" + id.Host,
+		platform: platform,
+	}
+}
+
+// registrySource finds plugin releases from a plugin registry.
+// The registry serves the following JSON endpoints:
+//
+//	GET /v1/plugins/{namespace}/{name}/versions
+//	  {"versions": ["1.0.0", "1.1.0"]}
+//	GET /v1/plugins/{namespace}/{name}/{version}/download/{os}/{arch}
+//	  {"filename": "...", "downloadUrl": "...", "shasumsUrl": "...", "shasumsSignatureUrl": "...", "docsUrl": "..."}
+//
+// The "docsUrl" field is optional.
+type registrySource struct {
+	client   *http.Client
+	baseURL  string
+	platform Platform
+}
+
+type registryVersions struct {
+	Versions []string `json:"versions"`
+}
+
+type registryDownload struct {
+	Filename            string `json:"filename"`
+	DownloadURL         string `json:"downloadUrl"`
+	ShasumsURL          string `json:"shasumsUrl"`
+	ShasumsSignatureURL string `json:"shasumsSignatureUrl"`
+	DocsURL             string `json:"docsUrl"`
+}
+
+func (s *registrySource) FindRelease(ctx context.Context, id ID, version string) (*Release, error) {
+	pluginURL := fmt.Sprintf(
+		"%s/v1/plugins/%s/%s",
+		s.baseURL,
+		url.PathEscape(id.Namespace),
+		url.PathEscape(id.Name),
+	)
+
+	if version == "" {
+		versions := &registryVersions{}
+		if err := getJSON(ctx, s.client, pluginURL+"/versions", nil, versions); err != nil {
+			return nil, err
+		}
+		version = latestVersion(versions.Versions)
+		if version == "" {
+			return nil, fmt.Errorf("%w: no versions of %s are available", ErrVersionNotFound, id)
+		}
+	}
+
+	download := &registryDownload{}
+	downloadURL := fmt.Sprintf(
+		"%s/%s/download/%s/%s",
+		pluginURL,
+		url.PathEscape(version),
+		url.PathEscape(s.platform.OS),
+		url.PathEscape(s.platform.Arch),
+	)
+	if err := getJSON(ctx, s.client, downloadURL, nil, download); err != nil {
+		return nil, err
+	}
+
+	return &Release{
+		Version:      normaliseVersion(version),
+		ArchiveName:  download.Filename,
+		ArchiveURL:   download.DownloadURL,
+		ChecksumsURL: download.ShasumsURL,
+		SignatureURL: download.ShasumsSignatureURL,
+		DocsURL:      download.DocsURL,
+	}, nil
+}
+
+// githubSource finds plugin releases from the releases of a GitHub
+// repository, where the ID namespace is the repository owner and
+// the ID name is the repository name.
+// Release archives are expected to include the OS and architecture in
+// their names, e.g. "bluelink-provider-aws_1.0.0_linux_amd64.tar.gz",
+// along with a checksums file, e.g. "bluelink-provider-aws_1.0.0_checksums.txt",
+// and optionally a signature of the checksums file with a ".sig" extension
+// and the plugin documentation, e.g. "bluelink-provider-aws_1.0.0_docs.json".
+type githubSource struct {
+	client   *http.Client
+	baseURL  string
+	platform Platform
+}
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (s *githubSource) FindRelease(ctx context.Context, id ID, version string) (*Release, error) {
+	repoURL := fmt.Sprintf(
+		"%s/repos/%s/%s/releases",
+		s.baseURL,
+		url.PathEscape(id.Namespace),
+		url.PathEscape(id.Name),
+	)
+
+	release := &githubRelease{}
+	releaseURL := repoURL + "/latest"
+	if version != "" {
+		releaseURL = repoURL + "/tags/" + url.PathEscape("v"+normaliseVersion(version))
+	}
+	if err := getJSON(ctx, s.client, releaseURL, githubHeaders(), release); err != nil {
+		return nil, err
+	}
+
+	platformSuffix := fmt.Sprintf("_%s_%s", s.platform.OS, s.platform.Arch)
+	result := &Release{Version: normaliseVersion(release.TagName)}
+	for _, asset := range release.Assets {
+		switch {
+		case isArchive(asset.Name) &&
+			strings.Contains(asset.Name, platformSuffix):
+			result.ArchiveName = asset.Name
+			result.ArchiveURL = asset.BrowserDownloadURL
+		case strings.HasSuffix(asset.Name, "checksums.txt"):
+			result.ChecksumsURL = asset.BrowserDownloadURL
+		case strings.HasSuffix(asset.Name, "checksums.txt.sig"):
+			result.SignatureURL = asset.BrowserDownloadURL
+		case asset.Name == DocsFileName || strings.HasSuffix(asset.Name, "_"+DocsFileName):
+			result.DocsURL = asset.BrowserDownloadURL
+		}
+	}
+
+	if result.ArchiveURL == "" {
+		return nil, fmt.Errorf(
+			"release %s of %s does not include an archive for %s/%s",
+			release.TagName,
+			id,
+			s.platform.OS,
+			s.platform.Arch,
+		)
+	}
+
+	return result, nil
+}
+
+// githubHeaders authenticates requests to the GitHub API when a
+// GITHUB_TOKEN is available, avoiding the low rate limit
+// for unauthenticated requests.
+func githubHeaders() map[string]string {
+	headers := map[string]string{
+		"Accept": "application/vnd.github+json",
+	}
+	if token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN")); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	return headers
+}
+
+func latestVersion(versions []string) string {
+	latest := ""
+	for _, version := range versions {
+		if latest == "" || CompareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+
+	return latest
+}
+
+func getJSON(
+	ctx context.Context,
+	client *http.Client,
+	target string,
+	headers map[string]string,
+	result any,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrVersionNotFound, target)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf(
+			"request to %s failed with status %d: %s",
+			target,
+			resp.StatusCode,
+			strings.TrimSpace(string(body)),
+		)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+var archiveExtensions = []string{".tar.gz", ".tgz", ".zip"}
+
+func isArchive(name string) bool {
+	return slices.ContainsFunc(archiveExtensions, func(ext string) bool {
+		return strings.HasSuffix(name, ext)
+	})
+}
@@ -0,0 +1,101 @@
+package plugins
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches the characters allowed in a semantic version,
+// versions are used as directory names so this also guards against
+// versions that would escape the plugin directory.
+var versionPattern = regexp.MustCompile(`^[0-9][0-9A-Za-z.+-]*$`)
+
+func validateVersion(version string) error {
+	if !versionPattern.MatchString(version) || strings.Contains(version, "..") {
+		return fmt.Errorf("invalid plugin version %q", version)
+	}
+
+	return nil
+}
+
+// CompareVersions compares two semantic versions, returning -1 when a is
+// older than b, 1 when a is newer than b and 0 when they are the same.
+// A leading "v" is ignored and pre-release versions are older than
+// the release they precede.
+func CompareVersions(a string, b string) int {
+	aCore, aPre, _ := strings.Cut(trimBuildMetadata(normaliseVersion(a)), "-")
+	bCore, bPre, _ := strings.Cut(trimBuildMetadata(normaliseVersion(b)), "-")
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := range max(len(aParts), len(bParts)) {
+		if cmp := compareIdentifiers(partAt(aParts, i), partAt(bParts, i)); cmp != 0 {
+			return cmp
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	return comparePreRelease(aPre, bPre)
+}
+
+func comparePreRelease(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := range min(len(aParts), len(bParts)) {
+		if cmp := compareIdentifiers(aParts[i], bParts[i]); cmp != 0 {
+			return cmp
+		}
+	}
+
+	return compareInts(len(aParts), len(bParts))
+}
+
+func compareIdentifiers(a string, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		return compareInts(aNum, bNum)
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func partAt(parts []string, index int) string {
+	if index < len(parts) {
+		return parts[index]
+	}
+
+	return "0"
+}
+
+func trimBuildMetadata(version string) string {
+	withoutMetadata, _, _ := strings.Cut(version, "+")
+	return withoutMetadata
+}
+
+// normaliseVersion strips the "v" prefix commonly used in release tags
+// so versions are stored the same way regardless of where they came from.
+func normaliseVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
@@ -0,0 +1,216 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	blueprintVersion   = "2025-11-02"
+	blueprintTransform = "celerity-2026-02-28"
+	handlerCodeDir     = "src"
+)
+
+// runtimes maps each supported language to the handler runtime
+// used in new blueprints, only runtimes that have a dev runtime image
+// for "celerity dev" are included.
+var runtimes = map[string]string{
+	consts.LanguageNodeJS: "nodejs24.x",
+	consts.LanguagePython: "python3.13",
+}
+
+// Languages lists the languages that new projects can be created for.
+var Languages = []string{
+	consts.LanguageNodeJS,
+	consts.LanguagePython,
+}
+
+func renderBlueprint(opts *Options, runtime string) (File, error) {
+	doc := orderedMap{
+		{"version", blueprintVersion},
+		{"transform", blueprintTransform},
+		{"variables", orderedMap{}},
+		{"resources", templateResources(opts, runtime)},
+	}
+
+	if opts.Format == FormatJSON {
+		content, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return File{}, err
+		}
+		return File{Path: "app.blueprint.jsonc", Content: append(content, '\n')}, nil
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return File{}, err
+	}
+	if err := encoder.Close(); err != nil {
+		return File{}, err
+	}
+
+	return File{Path: "app.blueprint.yaml", Content: buf.Bytes()}, nil
+}
+
+func templateResources(opts *Options, runtime string) orderedMap {
+	switch opts.Template {
+	case TemplateAPI:
+		return orderedMap{
+			{"api", orderedMap{
+				{"type", "celerity/api"},
+				{"metadata", orderedMap{
+					{"displayName", fmt.Sprintf("%s API", opts.Name)},
+				}},
+				{"linkSelector", applicationSelector(opts)},
+				{"spec", orderedMap{
+					{"protocols", []string{"http"}},
+				}},
+			}},
+			{"helloHandler", handlerResource(
+				opts,
+				runtime,
+				"Hello Handler",
+				"Hello",
+				"hello",
+				orderedMap{
+					{"celerity.handler.http", "true"},
+					{"celerity.handler.http.method", "GET"},
+					{"celerity.handler.http.path", "/hello"},
+				},
+			)},
+		}
+	case TemplateWorkflow:
+		return orderedMap{
+			{"workflow", orderedMap{
+				{"type", "celerity/workflow"},
+				{"metadata", orderedMap{
+					{"displayName", fmt.Sprintf("%s Workflow", opts.Name)},
+				}},
+				{"linkSelector", applicationSelector(opts)},
+				{"spec", orderedMap{
+					{"startAt", "processInput"},
+					{"states", orderedMap{
+						{"processInput", orderedMap{
+							{"type", "executeStep"},
+							{"description", "Process the input provided to the workflow."},
+							{"resultPath", "$.result"},
+							{"next", "done"},
+						}},
+						{"done", orderedMap{
+							{"type", "success"},
+							{"description", "The workflow has completed successfully."},
+						}},
+					}},
+				}},
+			}},
+			{"processInputHandler", handlerResource(
+				opts,
+				runtime,
+				"Process Input Handler",
+				"ProcessInput",
+				"processInput",
+				nil,
+			)},
+		}
+	}
+
+	return orderedMap{}
+}
+
+func applicationSelector(opts *Options) orderedMap {
+	return orderedMap{
+		{"byLabel", orderedMap{
+			{"application", opts.Name},
+		}},
+	}
+}
+
+func handlerResource(
+	opts *Options,
+	runtime string,
+	displayName string,
+	handlerName string,
+	function string,
+	annotations orderedMap,
+) orderedMap {
+	metadata := orderedMap{
+		{"displayName", displayName},
+		{"labels", orderedMap{
+			{"application", opts.Name},
+		}},
+	}
+	if len(annotations) > 0 {
+		metadata = append(metadata, mapEntry{"annotations", annotations})
+	}
+
+	return orderedMap{
+		{"type", "celerity/handler"},
+		{"metadata", metadata},
+		{"spec", orderedMap{
+			{"handlerName", fmt.Sprintf("%s-%s-v1", opts.Name, handlerName)},
+			{"codeLocation", "./" + handlerCodeDir},
+			{"handler", "handlers." + function},
+			{"runtime", runtime},
+			{"timeout", 30},
+		}},
+	}
+}
+
+// orderedMap is a mapping that keeps the order of its keys when
+// encoded as YAML or JSON, so generated documents read in the same
+// order as blueprints written by hand.
+type orderedMap []mapEntry
+
+type mapEntry struct {
+	Key   string
+	Value any
+}
+
+func (m orderedMap) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, entry := range m {
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(entry.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(
+			node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: entry.Key},
+			valueNode,
+		)
+	}
+
+	return node, nil
+}
+
+func (m orderedMap) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, entry := range m {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
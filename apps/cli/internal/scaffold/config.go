@@ -0,0 +1,58 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/devconfig/resolve"
+)
+
+const cliConfigTemplate = `# Configuration for the Celerity CLI, any flag can be set here
+# using the camelCase name of its config key.
+deployConfigFile = "celerity.deploy.json"
+connectProtocol = "unix"
+`
+
+const deployTargetConfigTemplate = `{
+  // The target environment the application is deployed to,
+  // e.g. "aws", "aws-serverless", "gcloud", "gcloud-serverless",
+  // "azure" or "azure-serverless".
+  "deployTarget": {
+    "name": %q
+  }
+}
+`
+
+func renderCLIConfig(_ *Options) File {
+	return File{Path: "celerity.config.toml", Content: []byte(cliConfigTemplate)}
+}
+
+func renderDeployTargetConfig(opts *Options) File {
+	return File{
+		Path:    "app.deploy.jsonc",
+		Content: fmt.Appendf(nil, deployTargetConfigTemplate, opts.DeployTarget),
+	}
+}
+
+// renderDeployConfig declares the provider that the deploy target
+// depends on so provider configuration has an obvious home.
+func renderDeployConfig(opts *Options) (File, error) {
+	provider := resolve.DeployTargetToProvider(opts.DeployTarget)
+	doc := orderedMap{
+		{"blueprintVariables", orderedMap{}},
+		{"providers", orderedMap{
+			{provider, orderedMap{}},
+		}},
+		{"transformers", orderedMap{
+			{"celerity", orderedMap{}},
+		}},
+		{"contextVariables", orderedMap{}},
+	}
+
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return File{}, err
+	}
+
+	return File{Path: "celerity.deploy.json", Content: append(content, '\n')}, nil
+}
@@ -0,0 +1,89 @@
+package scaffold
+
+import (
+	"path"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+)
+
+const nodeJSAPIHandlers = `// Example handlers for the resources in app.blueprint, each exported
+// function is referenced by the "handler" field of a celerity/handler resource.
+
+export async function hello() {
+  return {
+    status: 200,
+    headers: { "content-type": "application/json" },
+    body: JSON.stringify({ message: "Hello from Celerity!" }),
+  };
+}
+`
+
+const nodeJSWorkflowHandlers = `// Example handlers for the resources in app.blueprint, each exported
+// function is referenced by the "handler" field of a celerity/handler resource.
+
+export async function processInput(input: Record<string, unknown>) {
+  return { processed: true, input };
+}
+`
+
+const pythonAPIHandlers = `# Example handlers for the resources in app.blueprint, each function
+# is referenced by the "handler" field of a celerity/handler resource.
+
+from celerity_runtime_sdk import Request, RequestContext, Response, ResponseBuilder
+
+
+async def hello(req: Request, ctx: RequestContext) -> Response:
+    return (
+        ResponseBuilder()
+        .set_status(200)
+        .set_json_body({"message": "Hello from Celerity!"})
+        .build()
+    )
+`
+
+const pythonWorkflowHandlers = `# Example handlers for the resources in app.blueprint, each function
+# is referenced by the "handler" field of a celerity/handler resource.
+
+
+async def process_input(payload: dict) -> dict:
+    return {"processed": True, "input": payload}
+
+
+processInput = process_input
+`
+
+type exampleHandlers struct {
+	file     string
+	api      string
+	workflow string
+}
+
+var exampleHandlersByLanguage = map[string]exampleHandlers{
+	consts.LanguageNodeJS: {
+		file:     "handlers.ts",
+		api:      nodeJSAPIHandlers,
+		workflow: nodeJSWorkflowHandlers,
+	},
+	consts.LanguagePython: {
+		file:     "handlers.py",
+		api:      pythonAPIHandlers,
+		workflow: pythonWorkflowHandlers,
+	},
+}
+
+func renderExampleHandlers(opts *Options) (File, bool) {
+	examples, ok := exampleHandlersByLanguage[opts.Language]
+	if !ok {
+		return File{}, false
+	}
+
+	content := examples.api
+	if opts.Template == TemplateWorkflow {
+		content = examples.workflow
+	}
+
+	return File{
+		Path:    path.Join(handlerCodeDir, examples.file),
+		Content: []byte(content),
+	}, true
+}
@@ -0,0 +1,210 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Template is a starting point for the resources in a new project.
+type Template string
+
+const (
+	// TemplateAPI creates an HTTP API with a single handler.
+	TemplateAPI Template = "api"
+	// TemplateWorkflow creates a workflow with a single step
+	// backed by a handler.
+	TemplateWorkflow Template = "workflow"
+	// TemplateEmpty creates a blueprint without any resources.
+	TemplateEmpty Template = "empty"
+)
+
+// Templates lists the available project templates.
+var Templates = []Template{
+	TemplateAPI,
+	TemplateWorkflow,
+	TemplateEmpty,
+}
+
+// ParseTemplate parses a project template from its name.
+func ParseTemplate(value string) (Template, error) {
+	if slices.Contains(Templates, Template(value)) {
+		return Template(value), nil
+	}
+
+	return "", fmt.Errorf(
+		"unsupported template %q, must be one of %s",
+		value,
+		quoteAll(Templates),
+	)
+}
+
+// Format is the document format used for the blueprint of a new project.
+type Format string
+
+const (
+	// FormatYAML writes the blueprint as YAML.
+	FormatYAML Format = "yaml"
+	// FormatJSON writes the blueprint as JSON, the file is given the .jsonc
+	// extension that "celerity dev" looks for when finding the blueprint.
+	FormatJSON Format = "json"
+)
+
+// Formats lists the supported blueprint formats.
+var Formats = []Format{
+	FormatYAML,
+	FormatJSON,
+}
+
+// ParseFormat parses a blueprint format from its name.
+func ParseFormat(value string) (Format, error) {
+	if slices.Contains(Formats, Format(value)) {
+		return Format(value), nil
+	}
+
+	return "", fmt.Errorf(
+		"unsupported blueprint format %q, must be one of %s",
+		value,
+		quoteAll(Formats),
+	)
+}
+
+// Options holds the choices made for a new project.
+type Options struct {
+	// Dir is the directory to create the project in,
+	// it is created if it does not exist.
+	Dir string
+	// Name is the name of the application, used to name
+	// and label resources in the blueprint.
+	Name     string
+	Language string
+	Template Template
+	Format   Format
+	// DeployTarget is the name of the deploy target,
+	// e.g. "aws", "gcloud" or "azure-serverless".
+	DeployTarget string
+	// ExampleHandlers determines whether example handler source files
+	// are created for the handlers in the template.
+	ExampleHandlers bool
+	// Force allows existing files to be overwritten.
+	Force bool
+}
+
+// File is a file to be written for a new project.
+type File struct {
+	// Path is relative to the project directory.
+	Path    string
+	Content []byte
+}
+
+// Result describes the outcome of scaffolding a project.
+type Result struct {
+	// Files holds the paths of the files that were created,
+	// relative to the project directory.
+	Files []string
+	// Notes holds messages for the user about choices that could
+	// not be fully applied, e.g. example handlers not being available
+	// for the chosen language.
+	Notes []string
+}
+
+// Generate creates the files for a new project from the given options.
+// Unless Force is set, no files are written when any of the files
+// for the project already exist.
+func Generate(opts *Options) (*Result, error) {
+	files, notes, err := Render(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Force {
+		existing := []string{}
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(opts.Dir, file.Path)); err == nil {
+				existing = append(existing, file.Path)
+			}
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf(
+				"the following files already exist, use --force to overwrite them: %s",
+				strings.Join(existing, ", "),
+			)
+		}
+	}
+
+	result := &Result{Files: []string{}, Notes: notes}
+	for _, file := range files {
+		path := filepath.Join(opts.Dir, file.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, file.Path)
+	}
+
+	return result, nil
+}
+
+// Render produces the files for a new project without writing them.
+func Render(opts *Options) ([]File, []string, error) {
+	if opts.Name == "" {
+		return nil, nil, errors.New("a project name must be provided")
+	}
+
+	runtime, ok := runtimes[opts.Language]
+	if !ok {
+		return nil, nil, fmt.Errorf(
+			"unsupported language %q, must be one of %s",
+			opts.Language,
+			quoteAll(Languages),
+		)
+	}
+
+	blueprintFile, err := renderBlueprint(opts, runtime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []File{
+		blueprintFile,
+		renderCLIConfig(opts),
+		renderDeployTargetConfig(opts),
+	}
+
+	deployConfig, err := renderDeployConfig(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	files = append(files, deployConfig)
+
+	notes := []string{}
+	if opts.ExampleHandlers && opts.Template != TemplateEmpty {
+		handlerFile, hasExample := renderExampleHandlers(opts)
+		if hasExample {
+			files = append(files, handlerFile)
+		} else {
+			notes = append(notes, fmt.Sprintf(
+				"Example handlers are not available for %s yet, "+
+					"the handlers referenced in the blueprint will need to be written by hand.",
+				opts.Language,
+			))
+		}
+	}
+
+	return files, notes, nil
+}
+
+func quoteAll[T ~string](values []T) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,195 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/devconfig/resolve"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type ScaffoldTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestScaffoldTestSuite(t *testing.T) {
+	suite.Run(t, new(ScaffoldTestSuite))
+}
+
+func (s *ScaffoldTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *ScaffoldTestSuite) Test_generates_api_project_with_yaml_blueprint() {
+	result, err := Generate(s.options(TemplateAPI, FormatYAML))
+	s.Require().NoError(err)
+	s.Assert().Equal(
+		[]string{
+			"app.blueprint.yaml",
+			"celerity.config.toml",
+			"app.deploy.jsonc",
+			"celerity.deploy.json",
+			"src/handlers.ts",
+		},
+		result.Files,
+	)
+	s.Assert().Empty(result.Notes)
+
+	blueprint := s.readYAML("app.blueprint.yaml")
+	s.Assert().Equal(blueprintVersion, blueprint["version"])
+	s.Assert().Equal(blueprintTransform, blueprint["transform"])
+
+	resources := blueprint["resources"].(map[string]any)
+	s.Assert().Contains(resources, "api")
+	handler := resources["helloHandler"].(map[string]any)
+	s.Assert().Equal("celerity/handler", handler["type"])
+	spec := handler["spec"].(map[string]any)
+	s.Assert().Equal("orders-Hello-v1", spec["handlerName"])
+	s.Assert().Equal("handlers.hello", spec["handler"])
+	s.Assert().Equal("nodejs24.x", spec["runtime"])
+
+	handlers, err := os.ReadFile(filepath.Join(s.dir, "src", "handlers.ts"))
+	s.Require().NoError(err)
+	s.Assert().Contains(string(handlers), "export async function hello()")
+}
+
+func (s *ScaffoldTestSuite) Test_generates_workflow_project_with_json_blueprint() {
+	opts := s.options(TemplateWorkflow, FormatJSON)
+	opts.Language = consts.LanguagePython
+	opts.DeployTarget = "gcloud"
+	_, err := Generate(opts)
+	s.Require().NoError(err)
+
+	blueprint := s.readJSON("app.blueprint.jsonc")
+	resources := blueprint["resources"].(map[string]any)
+	workflow := resources["workflow"].(map[string]any)
+	s.Assert().Equal("celerity/workflow", workflow["type"])
+	spec := workflow["spec"].(map[string]any)
+	s.Assert().Equal("processInput", spec["startAt"])
+	handler := resources["processInputHandler"].(map[string]any)
+	s.Assert().Equal("python3.13", handler["spec"].(map[string]any)["runtime"])
+
+	deployConfig := s.readJSON("celerity.deploy.json")
+	s.Assert().Contains(deployConfig["providers"], "gcp")
+
+	handlers, err := os.ReadFile(filepath.Join(s.dir, "src", "handlers.py"))
+	s.Require().NoError(err)
+	s.Assert().Contains(string(handlers), "async def process_input(")
+}
+
+func (s *ScaffoldTestSuite) Test_empty_template_has_no_resources_or_handlers() {
+	result, err := Generate(s.options(TemplateEmpty, FormatYAML))
+	s.Require().NoError(err)
+	s.Assert().NotContains(result.Files, "src/handlers.ts")
+
+	blueprint := s.readYAML("app.blueprint.yaml")
+	s.Assert().Empty(blueprint["resources"])
+}
+
+func (s *ScaffoldTestSuite) Test_json_blueprint_is_found_by_dev_with_supported_runtime() {
+	opts := s.options(TemplateAPI, FormatJSON)
+	opts.Language = consts.LanguagePython
+	_, err := Generate(opts)
+	s.Require().NoError(err)
+
+	path, err := resolve.BlueprintPath(s.dir, "")
+	s.Require().NoError(err)
+	s.Assert().Equal(filepath.Join(s.dir, "app.blueprint.jsonc"), path)
+
+	bp, _, err := blueprint.LoadForDev(path)
+	s.Require().NoError(err)
+	handlers := blueprint.CollectHandlerInfo(bp)
+	s.Require().Len(handlers, 1)
+	runtime, err := blueprint.DetectRuntime(bp)
+	s.Require().NoError(err)
+	_, err = blueprint.ResolveRuntimeImage(runtime, "latest")
+	s.Assert().NoError(err)
+}
+
+func (s *ScaffoldTestSuite) Test_only_offers_languages_with_dev_runtime_images() {
+	for _, language := range Languages {
+		_, err := blueprint.ResolveRuntimeImage(runtimes[language], "latest")
+		s.Assert().NoError(err, language)
+	}
+
+	opts := s.options(TemplateAPI, FormatYAML)
+	opts.Language = consts.LanguageRust
+	_, _, err := Render(opts)
+	s.Assert().EqualError(err, `unsupported language "rust", must be one of "nodejs", "python"`)
+}
+
+func (s *ScaffoldTestSuite) Test_refuses_to_overwrite_existing_files_without_force() {
+	existing := filepath.Join(s.dir, "celerity.config.toml")
+	s.Require().NoError(os.WriteFile(existing, []byte("connectProtocol = \"tcp\"\n"), 0o644))
+
+	_, err := Generate(s.options(TemplateAPI, FormatYAML))
+	s.Require().Error(err)
+	s.Assert().Contains(err.Error(), "celerity.config.toml")
+	_, statErr := os.Stat(filepath.Join(s.dir, "app.blueprint.yaml"))
+	s.Assert().True(os.IsNotExist(statErr))
+
+	opts := s.options(TemplateAPI, FormatYAML)
+	opts.Force = true
+	_, err = Generate(opts)
+	s.Require().NoError(err)
+	content, err := os.ReadFile(existing)
+	s.Require().NoError(err)
+	s.Assert().Equal(cliConfigTemplate, string(content))
+}
+
+func (s *ScaffoldTestSuite) Test_fails_for_unsupported_language() {
+	opts := s.options(TemplateAPI, FormatYAML)
+	opts.Language = "cobol"
+	_, _, err := Render(opts)
+	s.Assert().EqualError(err, `unsupported language "cobol", must be one of "nodejs", "python"`)
+}
+
+func (s *ScaffoldTestSuite) Test_parses_templates_and_formats() {
+	template, err := ParseTemplate("workflow")
+	s.Require().NoError(err)
+	s.Assert().Equal(TemplateWorkflow, template)
+
+	_, err = ParseTemplate("spa")
+	s.Assert().EqualError(err, `unsupported template "spa", must be one of "api", "workflow", "empty"`)
+
+	format, err := ParseFormat("json")
+	s.Require().NoError(err)
+	s.Assert().Equal(FormatJSON, format)
+
+	_, err = ParseFormat("toml")
+	s.Assert().EqualError(err, `unsupported blueprint format "toml", must be one of "yaml", "json"`)
+}
+
+func (s *ScaffoldTestSuite) options(template Template, format Format) *Options {
+	return &Options{
+		Dir:             s.dir,
+		Name:            "orders",
+		Language:        consts.LanguageNodeJS,
+		Template:        template,
+		Format:          format,
+		DeployTarget:    "aws",
+		ExampleHandlers: true,
+	}
+}
+
+func (s *ScaffoldTestSuite) readYAML(path string) map[string]any {
+	content, err := os.ReadFile(filepath.Join(s.dir, path))
+	s.Require().NoError(err)
+	doc := map[string]any{}
+	s.Require().NoError(yaml.Unmarshal(content, &doc))
+	return doc
+}
+
+func (s *ScaffoldTestSuite) readJSON(path string) map[string]any {
+	content, err := os.ReadFile(filepath.Join(s.dir, path))
+	s.Require().NoError(err)
+	doc := map[string]any{}
+	s.Require().NoError(json.Unmarshal(content, &doc))
+	return doc
+}
@@ -33,12 +33,20 @@ type MockDeployEngine struct {
 	StreamChangeStagingEventsFn func(ctx context.Context, changesetID string, streamTo chan<- types.ChangeStagingEvent, errChan chan<- error) error
 	StreamChangeStagingErr      error
 
+	// CleanupChangesetsCalls counts the calls to CleanupChangesets.
+	CleanupChangesetsCalls int
+	CleanupChangesetsErr   error
+
 	CreateBlueprintInstanceResult *state.InstanceState
 	CreateBlueprintInstanceErr    error
 
 	UpdateBlueprintInstanceResult *state.InstanceState
 	UpdateBlueprintInstanceErr    error
 
+	// GetBlueprintInstanceFn allows the instance state to be controlled
+	// per call. If set, it is called directly; otherwise
+	// GetBlueprintInstanceResult and GetBlueprintInstanceErr are returned.
+	GetBlueprintInstanceFn     func(ctx context.Context, instanceID string) (*state.InstanceState, error)
 	GetBlueprintInstanceResult *state.InstanceState
 	GetBlueprintInstanceErr    error
 
@@ -110,7 +118,8 @@ func (m *MockDeployEngine) StreamChangeStagingEvents(
 }
 
 func (m *MockDeployEngine) CleanupChangesets(_ context.Context) error {
-	return nil
+	m.CleanupChangesetsCalls += 1
+	return m.CleanupChangesetsErr
 }
 
 func (m *MockDeployEngine) CreateBlueprintInstance(_ context.Context, _ *types.BlueprintInstancePayload) (*state.InstanceState, error) {
@@ -121,7 +130,10 @@ func (m *MockDeployEngine) UpdateBlueprintInstance(_ context.Context, _ string,
 	return m.UpdateBlueprintInstanceResult, m.UpdateBlueprintInstanceErr
 }
 
-func (m *MockDeployEngine) GetBlueprintInstance(_ context.Context, _ string) (*state.InstanceState, error) {
+func (m *MockDeployEngine) GetBlueprintInstance(ctx context.Context, instanceID string) (*state.InstanceState, error) {
+	if m.GetBlueprintInstanceFn != nil {
+		return m.GetBlueprintInstanceFn(ctx, instanceID)
+	}
 	return m.GetBlueprintInstanceResult, m.GetBlueprintInstanceErr
 }
 
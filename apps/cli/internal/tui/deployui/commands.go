@@ -0,0 +1,117 @@
+package deployui
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+)
+
+var errDeployStreamClosed = errors.New(
+	"deployment event stream closed before the deployment finished",
+)
+
+// ErrDeployDeclined is set as the model error when the user declines
+// to deploy the staged changes, this allows the command to exit with
+// a non-zero status so a declined deployment can be told apart from
+// a successful deployment.
+var ErrDeployDeclined = errors.New("deployment cancelled, the staged changes were not deployed")
+
+// ErrDeployInterrupted is set as the model error when the user quits
+// before the deployment has finished.
+var ErrDeployInterrupted = errors.New(
+	"interrupted before the deployment finished, a deployment that has already started " +
+		"will continue in the deploy engine",
+)
+
+// StagedChangesMsg is sent when change staging has completed.
+type StagedChangesMsg struct {
+	staged *deploy.StagedChanges
+}
+
+// DeployStartedMsg is sent when the deploy engine has accepted
+// the change set for deployment.
+type DeployStartedMsg struct {
+	instanceID string
+}
+
+// DeployEventMsg is sent for each event received in the
+// deployment event stream.
+type DeployEventMsg struct {
+	event *types.BlueprintInstanceEvent
+}
+
+// DeployErrMsg is sent when an error occurs in any stage of the deployment.
+type DeployErrMsg struct {
+	err error
+}
+
+func stageChangesCmd(model DeployModel) tea.Cmd {
+	return func() tea.Msg {
+		staged, err := deploy.StageChanges(
+			model.ctx,
+			model.engine,
+			model.opts,
+			nil,
+			model.logger,
+		)
+		if err != nil {
+			return DeployErrMsg{err}
+		}
+
+		return StagedChangesMsg{staged}
+	}
+}
+
+func startDeploymentCmd(model DeployModel) tea.Cmd {
+	return func() tea.Msg {
+		instanceID, err := deploy.Start(
+			model.ctx,
+			model.engine,
+			model.opts,
+			model.staged.ChangesetID,
+			model.logger,
+		)
+		if err != nil {
+			return DeployErrMsg{err}
+		}
+
+		err = model.engine.StreamBlueprintInstanceEvents(
+			model.ctx,
+			instanceID,
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return DeployErrMsg{err}
+		}
+
+		return DeployStartedMsg{instanceID}
+	}
+}
+
+func waitForNextEventCmd(model DeployModel) tea.Cmd {
+	return func() tea.Msg {
+		event, open := <-model.eventStream
+		if !open {
+			return DeployErrMsg{errDeployStreamClosed}
+		}
+
+		return DeployEventMsg{&event}
+	}
+}
+
+func checkForErrCmd(model DeployModel) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		select {
+		case <-time.After(1 * time.Second):
+			break
+		case newErr := <-model.errStream:
+			err = newErr
+		}
+		return DeployErrMsg{err}
+	}
+}
@@ -0,0 +1,332 @@
+package deployui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	headingStyle  = lipgloss.NewStyle().Bold(true).MarginLeft(2)
+	lineStyle     = lipgloss.NewStyle().MarginLeft(4)
+	reasonStyle   = lipgloss.NewStyle().MarginLeft(8).Foreground(lipgloss.Color("#dc2626"))
+	errorStyle    = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#dc2626"))
+	successStyle  = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#16a34a"))
+	mutedStyle    = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#6b7280"))
+	quitTextStyle = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+
+	actionStyles = map[deploy.Action]lipgloss.Style{
+		deploy.ActionCreate:   lipgloss.NewStyle().Foreground(lipgloss.Color("#16a34a")),
+		deploy.ActionUpdate:   lipgloss.NewStyle().Foreground(lipgloss.Color("#f97316")),
+		deploy.ActionRecreate: lipgloss.NewStyle().Foreground(lipgloss.Color("#d97706")),
+		deploy.ActionRemove:   lipgloss.NewStyle().Foreground(lipgloss.Color("#dc2626")),
+	}
+	inProgressStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#2563eb"))
+	rollingBackStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#d97706"))
+	failedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#dc2626"))
+	completeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#16a34a"))
+)
+
+type deployStage uint32
+
+const (
+	deployStageStaging deployStage = iota
+	deployStageConfirm
+	deployStageDeploying
+	deployStageFinished
+)
+
+// DeployModel is the model for the interactive deploy command,
+// it stages changes, asks the user to confirm the changes and then
+// renders the live progress of the deployment.
+type DeployModel struct {
+	stage       deployStage
+	engine      engine.DeployEngine
+	opts        *deploy.Options
+	logger      *zap.Logger
+	styles      *styles.CelerityStyles
+	spinner     spinner.Model
+	staged      *deploy.StagedChanges
+	summary     *deploy.Summary
+	instanceID  string
+	progress    *deploy.Progress
+	eventStream chan types.BlueprintInstanceEvent
+	errStream   chan error
+	quitting    bool
+	declined    bool
+	// ctx is cancelled when the user quits so that change staging
+	// and the deployment event stream stop with the program.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// Error holds an error that caused the deployment to fail,
+	// this includes deployments that finished with a failed status.
+	Error error
+}
+
+func (m DeployModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, stageChangesCmd(m))
+}
+
+func (m DeployModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case StagedChangesMsg:
+		m.staged = msg.staged
+		m.summary = deploy.Summarise(msg.staged.Changes)
+		if !m.summary.HasChanges() {
+			m.stage = deployStageFinished
+			m.cancel()
+			return m, tea.Quit
+		}
+		m.stage = deployStageConfirm
+	case DeployStartedMsg:
+		m.instanceID = msg.instanceID
+		return m, tea.Batch(waitForNextEventCmd(m), checkForErrCmd(m))
+	case DeployEventMsg:
+		if m.progress.Apply(msg.event) {
+			m.stage = deployStageFinished
+			m.Error = m.progress.Err()
+			m.cancel()
+			return m, tea.Quit
+		}
+		return m, waitForNextEventCmd(m)
+	case DeployErrMsg:
+		if msg.err != nil {
+			m.Error = msg.err
+			m.stage = deployStageFinished
+			m.cancel()
+			return m, tea.Quit
+		}
+		if m.stage == deployStageDeploying {
+			return m, checkForErrCmd(m)
+		}
+	}
+
+	return m, nil
+}
+
+func (m DeployModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		if m.stage != deployStageFinished {
+			m.Error = ErrDeployInterrupted
+		}
+		m.cancel()
+		return m, tea.Quit
+	}
+
+	if m.stage != deployStageConfirm {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.stage = deployStageDeploying
+		return m, startDeploymentCmd(m)
+	case "n", "N", "esc", "q":
+		m.declined = true
+		m.Error = ErrDeployDeclined
+		m.cancel()
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m DeployModel) View() string {
+	if m.quitting {
+		return m.quitView()
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+
+	switch m.stage {
+	case deployStageStaging:
+		sb.WriteString(fmt.Sprintf("  %s Staging changes for %s...\n", m.spinner.View(), m.opts.BlueprintFile))
+	case deployStageConfirm:
+		sb.WriteString(m.summaryView())
+		if m.declined {
+			sb.WriteString(mutedStyle.Render("Deployment cancelled, no changes were made."))
+		} else {
+			sb.WriteString(m.styles.Selectable.MarginLeft(2).Render("Deploy these changes? (y/n)"))
+		}
+		sb.WriteString("\n")
+	case deployStageDeploying, deployStageFinished:
+		sb.WriteString(m.resultView())
+	}
+
+	return sb.String()
+}
+
+func (m DeployModel) quitView() string {
+	if m.stage == deployStageDeploying && m.instanceID == "" {
+		// The request to start the deployment may have reached the deploy engine
+		// before it was cancelled, there is no instance ID to point the user to.
+		return quitTextStyle.Render(
+			"Stopped before the deploy engine confirmed that the deployment had started, " +
+				"check the blueprint instances in the deploy engine before deploying again.",
+		)
+	}
+
+	if m.stage == deployStageDeploying {
+		return quitTextStyle.Render(
+			fmt.Sprintf(
+				"Stopped watching the deployment, it will continue in the deploy engine (instance %s).",
+				m.instanceID,
+			),
+		)
+	}
+
+	return quitTextStyle.Render("Deployment cancelled, no changes were made.")
+}
+
+func (m DeployModel) summaryView() string {
+	sb := strings.Builder{}
+	sb.WriteString(headingStyle.Render("Changes"))
+	sb.WriteString("\n")
+	for _, change := range m.summary.Changes {
+		sb.WriteString(lineStyle.Render(actionStyles[change.Action].Render(change.String())))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(headingStyle.Render("Plan: " + m.summary.Totals()))
+	sb.WriteString("\n\n")
+	return sb.String()
+}
+
+func (m DeployModel) resultView() string {
+	sb := strings.Builder{}
+	if m.summary != nil && !m.summary.HasChanges() {
+		sb.WriteString(mutedStyle.Render("No changes to deploy."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	if m.progress != nil {
+		sb.WriteString(m.progressView())
+	}
+
+	switch {
+	case m.stage == deployStageDeploying:
+		sb.WriteString(fmt.Sprintf("\n  %s %s...\n", m.spinner.View(), m.instanceStatusLabel()))
+	case m.Error != nil:
+		sb.WriteString("\n")
+		sb.WriteString(errorStyle.Render(m.Error.Error()))
+		sb.WriteString("\n")
+	default:
+		sb.WriteString("\n")
+		sb.WriteString(successStyle.Render(
+			fmt.Sprintf("Deployment finished: %s (instance %s)", m.instanceStatusLabel(), m.instanceID),
+		))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func (m DeployModel) progressView() string {
+	elements := m.progress.Elements()
+	if len(elements) == 0 {
+		return ""
+	}
+
+	nameWidth := 0
+	for _, element := range elements {
+		nameWidth = max(nameWidth, len(element.Kind)+len(element.Name)+1)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(headingStyle.Render("Progress"))
+	sb.WriteString("\n")
+	for _, element := range elements {
+		label := fmt.Sprintf("%-*s", nameWidth, fmt.Sprintf("%s %s", element.Kind, element.Name))
+		sb.WriteString(lineStyle.Render(
+			fmt.Sprintf("%s %s  %s", m.elementIcon(element), label, elementStatusStyle(element).Render(element.Status)),
+		))
+		sb.WriteString("\n")
+		for _, reason := range element.FailureReasons {
+			sb.WriteString(reasonStyle.Render(reason))
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func (m DeployModel) elementIcon(element *deploy.ElementState) string {
+	switch {
+	case element.RollingBack:
+		return rollingBackStyle.Render("↺")
+	case element.InProgress:
+		return m.spinner.View()
+	case element.Failed:
+		return failedStyle.Render("✗")
+	default:
+		return completeStyle.Render("✓")
+	}
+}
+
+func elementStatusStyle(element *deploy.ElementState) lipgloss.Style {
+	switch {
+	case element.RollingBack:
+		return rollingBackStyle
+	case element.InProgress:
+		return inProgressStyle
+	case element.Failed:
+		return failedStyle
+	default:
+		return completeStyle
+	}
+}
+
+func (m DeployModel) instanceStatusLabel() string {
+	if m.progress == nil {
+		return "deploying"
+	}
+
+	label := engine.InstanceStatusLabel(m.progress.InstanceStatus())
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// NewDeployApp creates a new interactive deployment model
+// for the blueprint and options provided.
+func NewDeployApp(
+	deployEngine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *deploy.Options,
+	celerityStyles *styles.CelerityStyles,
+) *DeployModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DeployModel{
+		stage:       deployStageStaging,
+		engine:      deployEngine,
+		opts:        opts,
+		logger:      logger,
+		styles:      celerityStyles,
+		spinner:     s,
+		progress:    deploy.NewProgress(),
+		eventStream: make(chan types.BlueprintInstanceEvent),
+		errStream:   make(chan error),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
@@ -0,0 +1,234 @@
+package docsui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/docs"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+)
+
+var (
+	headingStyle = lipgloss.NewStyle().Bold(true).MarginLeft(2)
+	mutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+	helpStyle    = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#6b7280"))
+)
+
+const (
+	// maxContentWidth keeps the docs readable in wide terminals.
+	maxContentWidth = 100
+	// chromeHeight is the number of lines used by the heading,
+	// search input and help text around the list or viewport.
+	chromeHeight = 5
+)
+
+// DocsModel is the model for browsing the documentation of the resource
+// types provided by locally available plugins.
+// The model starts with a searchable list of resource types and switches
+// to a scrollable view of the docs when a resource type is selected.
+type DocsModel struct {
+	catalog  *docs.Catalog
+	styles   *styles.CelerityStyles
+	search   textinput.Model
+	matches  []*docs.Entry
+	cursor   int
+	viewing  *docs.Entry
+	viewport viewport.Model
+	width    int
+	height   int
+	quitting bool
+}
+
+func (m DocsModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m DocsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-chromeHeight, 1)
+		if m.viewing != nil {
+			m.viewport.SetContent(m.renderEntry(m.viewing))
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+		if m.viewing != nil {
+			return m.updateViewing(msg)
+		}
+		return m.updateSearching(msg)
+	}
+
+	return m, nil
+}
+
+func (m DocsModel) updateSearching(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.quitting = true
+		return m, tea.Quit
+	case "up", "ctrl+p":
+		m.cursor = max(m.cursor-1, 0)
+		return m, nil
+	case "down", "ctrl+n":
+		m.cursor = min(m.cursor+1, max(len(m.matches)-1, 0))
+		return m, nil
+	case "enter":
+		if len(m.matches) == 0 {
+			return m, nil
+		}
+		return m.view(m.matches[m.cursor]), nil
+	}
+
+	var cmd tea.Cmd
+	query := m.search.Value()
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() != query {
+		m.matches = m.catalog.Search(m.search.Value())
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+func (m DocsModel) updateViewing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc", "backspace", "/":
+		m.viewing = nil
+		return m, textinput.Blink
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m DocsModel) view(entry *docs.Entry) DocsModel {
+	m.viewing = entry
+	m.viewport.SetContent(m.renderEntry(entry))
+	m.viewport.GotoTop()
+	return m
+}
+
+func (m DocsModel) renderEntry(entry *docs.Entry) string {
+	width := maxContentWidth
+	if m.width > 0 {
+		width = min(m.width-4, maxContentWidth)
+	}
+
+	content := docs.Render(entry, docs.RenderOptions{Width: width, Styled: true})
+	return lipgloss.NewStyle().MarginLeft(2).Render(content)
+}
+
+func (m DocsModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	if m.viewing != nil {
+		return m.viewingView()
+	}
+	return m.searchingView()
+}
+
+func (m DocsModel) viewingView() string {
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	sb.WriteString(headingStyle.Render(m.viewing.Resource.Type))
+	sb.WriteString("\n\n")
+	sb.WriteString(m.viewport.View())
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render(fmt.Sprintf(
+		"%3.f%% • ↑/↓ pgup/pgdn: scroll • esc: back to search • q: quit",
+		m.viewport.ScrollPercent()*100,
+	)))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func (m DocsModel) searchingView() string {
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	sb.WriteString(headingStyle.Render(
+		fmt.Sprintf("Plugin docs (%d resource types)", len(m.catalog.Entries)),
+	))
+	sb.WriteString("\n\n  ")
+	sb.WriteString(m.search.View())
+	sb.WriteString("\n\n")
+
+	if len(m.matches) == 0 {
+		sb.WriteString(helpStyle.Render("No resource types match the search."))
+		sb.WriteString("\n")
+	}
+
+	start, end := m.visibleMatches()
+	for i := start; i < end; i += 1 {
+		entry := m.matches[i]
+		line := entry.Resource.Type
+		if i == m.cursor {
+			sb.WriteString(m.styles.Selected.Render("> " + line))
+		} else {
+			sb.WriteString(m.styles.Selectable.Render("  " + line))
+		}
+
+		if entry.Resource.Label != "" {
+			sb.WriteString(mutedStyle.Render("  " + entry.Resource.Label))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("type to search • ↑/↓: select • enter: view docs • esc: quit"))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// visibleMatches finds the range of matches that fit in the terminal,
+// keeping the selected match in view.
+func (m DocsModel) visibleMatches() (int, int) {
+	limit := len(m.matches)
+	if m.height > 0 {
+		limit = max(m.height-chromeHeight-2, 1)
+	}
+
+	start := max(m.cursor-limit+1, 0)
+	end := min(start+limit, len(m.matches))
+	return start, end
+}
+
+// NewDocsApp creates a new docs browser for the resource types in the
+// catalog, starting with the given search query.
+// The docs for the selected entry are shown straight away when it is set.
+func NewDocsApp(catalog *docs.Catalog, query string, selected *docs.Entry) DocsModel {
+	search := textinput.New()
+	search.Placeholder = "Search resource types"
+	search.Prompt = "/ "
+	search.SetValue(query)
+	search.Focus()
+
+	model := DocsModel{
+		catalog:  catalog,
+		styles:   styles.NewDefaultCelerityStyles(),
+		search:   search,
+		matches:  catalog.Search(query),
+		viewport: viewport.New(maxContentWidth, 20),
+	}
+
+	if selected != nil {
+		return model.view(selected)
+	}
+	return model
+}
@@ -0,0 +1,54 @@
+package driftui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/drift"
+)
+
+// DriftChecksMsg is sent when a round of drift checks for all
+// the watched instances has completed.
+type DriftChecksMsg struct {
+	reports []*drift.Report
+}
+
+// DriftTickMsg is sent when it is time for the next round of drift checks.
+type DriftTickMsg struct {
+	round int
+}
+
+// NotifyErrMsg is sent when a notification for new drift could not be sent.
+type NotifyErrMsg struct {
+	err error
+}
+
+func checkDriftCmd(model DriftWatchModel) tea.Cmd {
+	return func() tea.Msg {
+		reports := drift.CheckAll(
+			model.ctx,
+			model.engine,
+			model.targets,
+			model.config,
+			model.logger,
+		)
+		return DriftChecksMsg{reports}
+	}
+}
+
+func scheduleCheckCmd(interval time.Duration, round int) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return DriftTickMsg{round}
+	})
+}
+
+func notifyCmd(notifiers []drift.Notifier, report *drift.Report, newDrift []*drift.Resource) tea.Cmd {
+	return func() tea.Msg {
+		err := drift.NotifyAll(notifiers, report, newDrift)
+		if err != nil {
+			return NotifyErrMsg{err}
+		}
+
+		return nil
+	}
+}
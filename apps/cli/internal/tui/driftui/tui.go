@@ -0,0 +1,288 @@
+package driftui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/drift"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+var (
+	headingStyle  = lipgloss.NewStyle().Bold(true).MarginLeft(2)
+	lineStyle     = lipgloss.NewStyle().MarginLeft(2)
+	mutedStyle    = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#6b7280"))
+	quitTextStyle = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+
+	driftedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#f97316"))
+	newDriftStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#dc2626"))
+	noDriftStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#16a34a"))
+	failedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#dc2626"))
+)
+
+const detectedAtFormat = "2006-01-02 15:04:05"
+
+// DriftWatchModel is the model for the interactive drift watch command,
+// it periodically checks the watched instances for drift and renders
+// a live-updating table of the drifted resources.
+type DriftWatchModel struct {
+	engine    engine.DeployEngine
+	targets   []*drift.Target
+	config    *types.BlueprintOperationConfig
+	interval  time.Duration
+	notifiers []drift.Notifier
+	logger    *zap.Logger
+	spinner   spinner.Model
+	tracker   *drift.Tracker
+	reports   []*drift.Report
+	// newDrift holds the keys of the resources that newly drifted
+	// in the latest round of checks.
+	newDrift  map[string]bool
+	checking  bool
+	round     int
+	nextCheck time.Time
+	notifyErr error
+	quitting  bool
+	// ctx is cancelled when the user quits so that a check
+	// that is still running stops with the program.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (m DriftWatchModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, checkDriftCmd(m))
+}
+
+func (m DriftWatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			m.quitting = true
+			m.cancel()
+			return m, tea.Quit
+		case "r":
+			if m.checking {
+				return m, nil
+			}
+			// Moving on to the next round discards the scheduled check.
+			m.round += 1
+			m.checking = true
+			return m, checkDriftCmd(m)
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case DriftTickMsg:
+		if msg.round != m.round || m.checking {
+			return m, nil
+		}
+		m.checking = true
+		return m, checkDriftCmd(m)
+	case DriftChecksMsg:
+		return m.applyReports(msg.reports)
+	case NotifyErrMsg:
+		m.notifyErr = msg.err
+	}
+
+	return m, nil
+}
+
+func (m DriftWatchModel) applyReports(reports []*drift.Report) (tea.Model, tea.Cmd) {
+	m.reports = reports
+	m.newDrift = map[string]bool{}
+	cmds := []tea.Cmd{}
+	for _, report := range reports {
+		newDrift := m.tracker.Track(report)
+		for _, resource := range newDrift {
+			m.newDrift[resource.Key()] = true
+		}
+		if len(newDrift) > 0 {
+			cmds = append(cmds, notifyCmd(m.notifiers, report, newDrift))
+		}
+	}
+
+	m.checking = false
+	m.round += 1
+	m.nextCheck = time.Now().Add(m.interval)
+	cmds = append(cmds, scheduleCheckCmd(m.interval, m.round))
+	return m, tea.Batch(cmds...)
+}
+
+func (m DriftWatchModel) View() string {
+	if m.quitting {
+		return quitTextStyle.Render("Stopped watching for drift.")
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	sb.WriteString(headingStyle.Render(
+		fmt.Sprintf("Watching %d blueprint instance(s) for drift every %s", len(m.targets), m.interval),
+	))
+	sb.WriteString("\n\n")
+
+	if m.reports != nil {
+		sb.WriteString(m.tableView())
+		sb.WriteString("\n")
+	}
+
+	if m.checking || m.reports == nil {
+		sb.WriteString(fmt.Sprintf("  %s Checking for drift...\n", m.spinner.View()))
+	} else {
+		untilNext := max(time.Until(m.nextCheck).Round(time.Second), 0)
+		sb.WriteString(mutedStyle.Render(fmt.Sprintf("Next check in %s", untilNext)))
+		sb.WriteString("\n")
+	}
+
+	if m.notifyErr != nil {
+		sb.WriteString(mutedStyle.Render(fmt.Sprintf("Failed to send notification: %s", m.notifyErr)))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(mutedStyle.Render("r: check now • q: quit"))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+type tableRow struct {
+	instance   string
+	resource   string
+	resourceTy string
+	detectedAt string
+	style      lipgloss.Style
+	// status rows hold a message in place of the resource columns,
+	// the message does not count towards the width of the columns.
+	status bool
+}
+
+func (m DriftWatchModel) tableView() string {
+	rows := []tableRow{}
+	for _, report := range m.reports {
+		rows = append(rows, m.reportRows(report)...)
+	}
+
+	header := tableRow{
+		instance:   "INSTANCE",
+		resource:   "RESOURCE",
+		resourceTy: "TYPE",
+		detectedAt: "DETECTED",
+	}
+	widths := [3]int{
+		lipgloss.Width(header.instance),
+		lipgloss.Width(header.resource),
+		lipgloss.Width(header.resourceTy),
+	}
+	for _, row := range rows {
+		widths[0] = max(widths[0], lipgloss.Width(row.instance))
+		if row.status {
+			continue
+		}
+		widths[1] = max(widths[1], lipgloss.Width(row.resource))
+		widths[2] = max(widths[2], lipgloss.Width(row.resourceTy))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(headingStyle.Render(formatRow(header, widths)))
+	sb.WriteString("\n")
+	for _, row := range rows {
+		sb.WriteString(lineStyle.Render(row.style.Render(formatRow(row, widths))))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func (m DriftWatchModel) reportRows(report *drift.Report) []tableRow {
+	if report.Err != nil {
+		return []tableRow{{
+			instance: report.Label(),
+			resource: fmt.Sprintf("✗ check failed: %s", report.Err),
+			style:    failedStyle,
+			status:   true,
+		}}
+	}
+
+	if len(report.Resources) == 0 {
+		return []tableRow{{
+			instance: report.Label(),
+			resource: "✓ no drift detected",
+			style:    noDriftStyle,
+			status:   true,
+		}}
+	}
+
+	rows := make([]tableRow, 0, len(report.Resources))
+	for _, resource := range report.Resources {
+		row := tableRow{
+			instance:   report.Label(),
+			resource:   "~ " + resource.Path,
+			resourceTy: resource.Type,
+			style:      driftedStyle,
+		}
+		if !resource.DetectedAt.IsZero() {
+			row.detectedAt = resource.DetectedAt.Local().Format(detectedAtFormat)
+		}
+		if m.newDrift[resource.Key()] {
+			row.resource = "! " + resource.Path
+			row.style = newDriftStyle
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func formatRow(row tableRow, widths [3]int) string {
+	if row.status {
+		return fmt.Sprintf("%-*s  %s", widths[0], row.instance, row.resource)
+	}
+
+	return strings.TrimRight(
+		fmt.Sprintf(
+			"%-*s  %-*s  %-*s  %s",
+			widths[0], row.instance,
+			widths[1], row.resource,
+			widths[2], row.resourceTy,
+			row.detectedAt,
+		),
+		" ",
+	)
+}
+
+// NewDriftWatchApp creates a new interactive drift watch model
+// for the given blueprint instances.
+func NewDriftWatchApp(
+	deployEngine engine.DeployEngine,
+	logger *zap.Logger,
+	targets []*drift.Target,
+	config *types.BlueprintOperationConfig,
+	interval time.Duration,
+	notifiers []drift.Notifier,
+) *DriftWatchModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DriftWatchModel{
+		engine:    deployEngine,
+		targets:   targets,
+		config:    config,
+		interval:  interval,
+		notifiers: notifiers,
+		logger:    logger,
+		spinner:   s,
+		tracker:   drift.NewTracker(),
+		checking:  true,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
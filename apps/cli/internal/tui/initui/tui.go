@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/scaffold"
 )
 
 const listHeight = 14
@@ -63,88 +64,189 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	fmt.Fprint(w, fn(str))
 }
 
+type initStep uint32
+
+const (
+	initStepLanguage initStep = iota
+	initStepTemplate
+	initStepExampleHandlers
+	initStepDone
+)
+
+// InitModel is the model for the interactive prompts of the init command,
+// it asks for each of the project choices that were not provided as flags.
 type InitModel struct {
-	list     list.Model
-	choice   string
-	quitting bool
+	step  initStep
+	lists map[initStep]list.Model
+	// Language is the language/framework chosen for the project.
+	Language string
+	// Template is the project template chosen for the project.
+	Template string
+	// ExampleHandlers is set when the user chose to include example handlers.
+	ExampleHandlers bool
+	// Quitting is set when the user exits before making all the choices.
+	Quitting              bool
+	askForExampleHandlers bool
 }
 
-func (i InitModel) Init() tea.Cmd {
+func (m InitModel) Init() tea.Cmd {
 	return nil
 }
 
 func (m InitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.list.SetWidth(msg.Width)
+		for step, l := range m.lists {
+			l.SetWidth(msg.Width)
+			m.lists[step] = l
+		}
 		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "esc":
-			m.quitting = true
+			m.Quitting = true
 			return m, tea.Quit
 		case "enter":
-			i, ok := m.list.SelectedItem().(item)
+			i, ok := m.lists[m.step].SelectedItem().(item)
 			if ok {
-				m.choice = string(i.key)
+				m.choose(i.key)
 			}
-			return m, tea.Quit
+			m.step = m.nextStep(m.step + 1)
+			if m.step == initStepDone {
+				return m, tea.Quit
+			}
+			return m, nil
 		}
 	}
+
+	l, ok := m.lists[m.step]
+	if !ok {
+		return m, nil
+	}
 	var cmd tea.Cmd
-	m.list, cmd = m.list.Update(msg)
+	m.lists[m.step], cmd = l.Update(msg)
 	return m, cmd
 }
 
+func (m *InitModel) choose(key string) {
+	switch m.step {
+	case initStepLanguage:
+		m.Language = key
+	case initStepTemplate:
+		m.Template = key
+	case initStepExampleHandlers:
+		m.ExampleHandlers = key == "yes"
+	}
+}
+
+// nextStep finds the first step from the given step onwards that
+// still needs a choice from the user.
+func (m InitModel) nextStep(from initStep) initStep {
+	for step := from; step < initStepDone; step++ {
+		if m.needsChoice(step) {
+			return step
+		}
+	}
+
+	return initStepDone
+}
+
+func (m InitModel) needsChoice(step initStep) bool {
+	switch step {
+	case initStepLanguage:
+		return m.Language == ""
+	case initStepTemplate:
+		return m.Template == ""
+	case initStepExampleHandlers:
+		return m.askForExampleHandlers && m.Template != string(scaffold.TemplateEmpty)
+	}
+
+	return false
+}
+
 func (m InitModel) View() string {
-	if m.choice != "" {
-		return quitTextStyle.Render(fmt.Sprintf("%s? Sounds good to me.", m.choice))
+	if m.Quitting {
+		return quitTextStyle.Render("Project set up cancelled, no files were created.")
 	}
-	if m.quitting {
-		return quitTextStyle.Render("Not hungry? That’s cool.")
+
+	l, ok := m.lists[m.step]
+	if !ok {
+		return ""
 	}
-	return "\n" + m.list.View()
+
+	return "\n" + l.View()
 }
 
-func NewInitApp(initialLanguage string) *InitModel {
-	items := []list.Item{
-		item{
-			key:   consts.LanguageNodeJS,
-			label: "Node.js",
-		},
-		item{
-			key:   consts.LanguagePython,
-			label: "Python",
-		},
-		item{
-			key:   consts.LanguageJava,
-			label: "Java",
-		},
-		item{
-			key:   consts.LanguageDotNet,
-			label: ".NET",
-		},
-		item{
-			key:   consts.LanguageGo,
-			label: "Go",
-		},
-		item{
-			key:   consts.LanguageRust,
-			label: "Rust",
+// NewInitApp creates the model for the interactive init prompts,
+// prompts are skipped for the choices that are already provided.
+func NewInitApp(
+	initialLanguage string,
+	initialTemplate string,
+	askForExampleHandlers bool,
+) *InitModel {
+	model := &InitModel{
+		lists: map[initStep]list.Model{
+			initStepLanguage: newList(
+				"What language/framework do you want to use for your project?",
+				languageItems(),
+			),
+			initStepTemplate: newList(
+				"What kind of project do you want to create?",
+				[]list.Item{
+					item{key: string(scaffold.TemplateAPI), label: "HTTP API"},
+					item{key: string(scaffold.TemplateWorkflow), label: "Workflow"},
+					item{key: string(scaffold.TemplateEmpty), label: "Empty project"},
+				},
+			),
+			initStepExampleHandlers: newList(
+				"Do you want to include example handlers?",
+				[]list.Item{
+					item{key: "yes", label: "Yes"},
+					item{key: "no", label: "No"},
+				},
+			),
 		},
+		Language:              initialLanguage,
+		Template:              initialTemplate,
+		askForExampleHandlers: askForExampleHandlers,
+	}
+	model.step = model.nextStep(initStepLanguage)
+
+	return model
+}
+
+// Done determines whether all the choices have been made
+// and the project can be created.
+func (m InitModel) Done() bool {
+	return m.step == initStepDone && !m.Quitting
+}
+
+var languageLabels = map[string]string{
+	consts.LanguageNodeJS: "Node.js",
+	consts.LanguagePython: "Python",
+}
+
+func languageItems() []list.Item {
+	items := make([]list.Item, 0, len(scaffold.Languages))
+	for _, language := range scaffold.Languages {
+		items = append(items, item{
+			key:   language,
+			label: languageLabels[language],
+		})
 	}
 
+	return items
+}
+
+func newList(title string, items []list.Item) list.Model {
 	const defaultWidth = 20
 
 	l := list.New(items, itemDelegate{}, defaultWidth, listHeight)
-	l.Title = "What language/framework do you want to use for your project?"
+	l.Title = title
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.Styles.Title = titleStyle
 	l.Styles.PaginationStyle = paginationStyle
 	l.Styles.HelpStyle = helpStyle
-	return &InitModel{
-		list:   l,
-		choice: initialLanguage,
-	}
+	return l
 }